@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+type diffLine struct {
+	kind byte // ' ', '-' or '+'
+	text string
+	// oldLine and newLine are the 0-based line numbers reached before this line.
+	oldLine int
+	newLine int
+}
+
+// unifiedDiff renders the changes from oldText to newText in unified format.
+// An empty oldText is treated as a new file.
+func unifiedDiff(path string, oldText string, newText string) string {
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	if oldText == "" {
+		fmt.Fprintf(&b, "--- /dev/null\n")
+	} else {
+		fmt.Fprintf(&b, "--- a/%s\n", path)
+	}
+	fmt.Fprintf(&b, "+++ b/%s\n", path)
+
+	for start := 0; start < len(lines); {
+		first := nextChange(lines, start)
+		if first < 0 {
+			break
+		}
+
+		// Extend the hunk while the next change is close enough to share context.
+		last := first
+		for {
+			next := nextChange(lines, last+1)
+			if next < 0 || next-last > 2*diffContext {
+				break
+			}
+			last = next
+		}
+
+		from := max(first-diffContext, 0)
+		to := min(last+diffContext+1, len(lines))
+
+		oldCount, newCount := 0, 0
+		for _, line := range lines[from:to] {
+			if line.kind != '+' {
+				oldCount++
+			}
+			if line.kind != '-' {
+				newCount++
+			}
+		}
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(lines[from].oldLine, oldCount),
+			hunkRange(lines[from].newLine, newCount),
+		)
+		for _, line := range lines[from:to] {
+			fmt.Fprintf(&b, "%c%s\n", line.kind, line.text)
+		}
+
+		start = to
+	}
+
+	return b.String()
+}
+
+// diffLines computes a line-level edit script from the longest common subsequence of a and b.
+func diffLines(a []string, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{kind: ' ', text: a[i], oldLine: i, newLine: j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			lines = append(lines, diffLine{kind: '+', text: b[j], oldLine: i, newLine: j})
+			j++
+		default:
+			lines = append(lines, diffLine{kind: '-', text: a[i], oldLine: i, newLine: j})
+			i++
+		}
+	}
+
+	return lines
+}
+
+func nextChange(lines []diffLine, from int) int {
+	for i := from; i < len(lines); i++ {
+		if lines[i].kind != ' ' {
+			return i
+		}
+	}
+	return -1
+}
+
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
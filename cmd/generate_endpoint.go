@@ -0,0 +1,724 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	endpointNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	pathParamPattern    = regexp.MustCompile(`:([A-Za-z][A-Za-z0-9]*)`)
+	routeVerbs          = map[string]bool{"Get": true, "Post": true, "Put": true, "Patch": true, "Delete": true}
+)
+
+// EndpointOptions configures GenerateEndpoint.
+type EndpointOptions struct {
+	ModuleName string
+	Name       string
+	Method     string
+	Path       string
+	// Entity overrides the entity the endpoint works on; defaults to the first entity in the repository interface.
+	Entity string
+	DryRun bool
+}
+
+type EndpointParam struct {
+	Name   string
+	Column string
+	IsUUID bool
+}
+
+// EndpointData is rendered once per layer; Receiver, Impl and Dependency change between layers.
+type EndpointData struct {
+	Name           string
+	Kind           string
+	Entity         string
+	EntityVar      string
+	HumanEntity    string
+	PathParams     []EndpointParam
+	HasBody        bool
+	RequestType    string
+	Params         string
+	RepoParams     string
+	UsecaseParams  string
+	Results        string
+	HandlerArgs    string
+	ResponseFunc   string
+	Where          string
+	ResultVar      string
+	SuccessStatus  string
+	SuccessMessage string
+
+	Receiver   string
+	Impl       string
+	Dependency string
+}
+
+// layerFile is a parsed layer package file of a module.
+type layerFile struct {
+	layer string
+	path  string
+	src   []byte
+	fset  *token.FileSet
+	file  *ast.File
+	iface *ast.InterfaceType
+	impl  string
+	recv  string
+	dep   string
+}
+
+// GenerateEndpoint adds one endpoint to an existing module: an interface method and implementation
+// in the repository, usecase and handler layers, the request DTO for endpoints with a body, plus the
+// route registration. The usecase method answers 501 until it is written, so a generated endpoint
+// never reads or writes data on its own. It refuses to run when any layer already declares a method
+// with the same name or the route is already registered.
+func GenerateEndpoint(opts EndpointOptions) error {
+	if !moduleNamePattern.MatchString(opts.ModuleName) {
+		return fmt.Errorf("invalid module name %q", opts.ModuleName)
+	}
+	if !endpointNamePattern.MatchString(opts.Name) {
+		return fmt.Errorf("invalid endpoint name %q: use an exported Go identifier, e.g. FindBySlug", opts.Name)
+	}
+
+	method := strings.ToUpper(opts.Method)
+	verb := capitalize([]string{strings.ToLower(method)})
+	if !routeVerbs[verb] {
+		return fmt.Errorf("unsupported HTTP method %q", opts.Method)
+	}
+	if !strings.HasPrefix(opts.Path, "/") {
+		return fmt.Errorf("path %q must start with /", opts.Path)
+	}
+
+	moduleDir := filepath.Join("internal", "modules", opts.ModuleName)
+	if _, err := os.Stat(moduleDir); err != nil {
+		return fmt.Errorf("module %s not found: %w", opts.ModuleName, err)
+	}
+
+	var layers []*layerFile
+	for _, layer := range []string{"repository", "usecase", "handler"} {
+		lf, err := loadLayer(filepath.Join(moduleDir, layer), layer)
+		if err != nil {
+			return err
+		}
+		if err := lf.ensureMethodAbsent(opts.Name); err != nil {
+			return err
+		}
+		layers = append(layers, lf)
+	}
+
+	entityName := opts.Entity
+	if entityName == "" {
+		entityName = firstEntity(layers[0].iface)
+		if entityName == "" {
+			return fmt.Errorf("could not infer the entity of module %s; pass --entity", opts.ModuleName)
+		}
+	}
+
+	data, err := newEndpointData(opts.Name, method, opts.Path, entityName)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.ParseFS(generatorTemplates, "templates/endpoint/endpoint.go.tmpl")
+	if err != nil {
+		return err
+	}
+
+	var changes []fileChange
+
+	dtoChange, err := addEndpointDTO(filepath.Join(moduleDir, "dto"), tmpl, &data)
+	if err != nil {
+		return err
+	}
+	if dtoChange != nil {
+		changes = append(changes, *dtoChange)
+	}
+
+	for _, lf := range layers {
+		change, err := lf.addEndpoint(tmpl, data)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, *change)
+	}
+
+	routeFile := filepath.Join("internal", "routes", opts.ModuleName+"_route.go")
+	routeChange, err := editFile(routeFile, func(src []byte) ([]byte, error) {
+		return registerEndpointRoute(src, "Init"+capitalize(strings.Split(opts.ModuleName, "-"))+"Routes", verb, opts.Path, opts.Name)
+	})
+	if err != nil {
+		return err
+	}
+	if routeChange != nil {
+		changes = append(changes, *routeChange)
+	}
+
+	if opts.DryRun {
+		for _, change := range changes {
+			fmt.Print(unifiedDiff(change.Path, change.Old, change.New))
+		}
+		return nil
+	}
+
+	if err := applyChanges(changes); err != nil {
+		return err
+	}
+
+	log.Info().Msgf("Endpoint %s %s added to module %s ✨", method, opts.Path, opts.ModuleName)
+	return nil
+}
+
+func newEndpointData(name string, method string, path string, entityName string) (EndpointData, error) {
+	data := EndpointData{
+		Name:        name,
+		Entity:      entityName,
+		EntityVar:   strings.ToLower(entityName[:1]) + entityName[1:],
+		HumanEntity: strings.ToLower(strings.Join(splitCamel(entityName), " ")),
+	}
+
+	seen := map[string]bool{}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		param := match[1]
+		if seen[param] {
+			return EndpointData{}, fmt.Errorf("path parameter %q appears more than once", param)
+		}
+		seen[param] = true
+
+		data.PathParams = append(data.PathParams, EndpointParam{
+			Name:   param,
+			Column: strings.ToLower(strings.Join(splitCamel(param), "_")),
+			IsUUID: param == "id" || strings.HasSuffix(param, "ID") || strings.HasSuffix(param, "Id"),
+		})
+	}
+
+	switch {
+	case method == http.MethodGet && len(data.PathParams) > 0:
+		data.Kind = "find"
+	case method == http.MethodGet:
+		data.Kind = "list"
+	case method == http.MethodPost:
+		data.Kind = "create"
+	case method == http.MethodPut || method == http.MethodPatch:
+		data.Kind = "update"
+	case method == http.MethodDelete:
+		data.Kind = "delete"
+	}
+
+	if (data.Kind == "update" || data.Kind == "delete") && len(data.PathParams) == 0 {
+		return EndpointData{}, fmt.Errorf("%s endpoints need a path parameter to select the %s, e.g. /:id", method, data.HumanEntity)
+	}
+
+	params := []string{"ctx context.Context"}
+	args := []string{"ctx"}
+	var where []string
+	var whereArgs []string
+	for _, param := range data.PathParams {
+		paramType := "string"
+		if param.IsUUID {
+			paramType = "uuid.UUID"
+		}
+		params = append(params, param.Name+" "+paramType)
+		args = append(args, param.Name)
+		where = append(where, param.Column+" = ?")
+		whereArgs = append(whereArgs, param.Name)
+	}
+
+	repoParams := slices.Clone(params)
+
+	// The client's body is bound to a request DTO generated for the endpoint, never to the entity,
+	// so it cannot set columns such as the ID or the owner.
+	data.HasBody = data.Kind == "create" || data.Kind == "update"
+	if data.HasBody {
+		data.RequestType = name + "Request"
+		params = append(params, "req *dto."+data.RequestType)
+		args = append(args, "&req")
+
+		switch data.Kind {
+		case "create":
+			repoParams = append(repoParams, "payload *entity."+entityName)
+		case "update":
+			repoParams = append(repoParams, "updates map[string]any")
+		}
+	}
+
+	data.RepoParams = strings.Join(repoParams, ", ")
+	data.UsecaseParams = strings.Join(params, ", ")
+	data.HandlerArgs = strings.Join(args, ", ")
+	if len(where) > 0 {
+		data.Where = fmt.Sprintf("Where(%q, %s)", strings.Join(where, " AND "), strings.Join(whereArgs, ", "))
+	}
+
+	data.ResultVar = data.EntityVar
+	data.SuccessStatus = "fiber.StatusOK"
+	switch data.Kind {
+	case "list":
+		data.Results = fmt.Sprintf("([]entity.%s, error)", entityName)
+		data.ResultVar = data.EntityVar + "s"
+		data.SuccessMessage = data.HumanEntity + "s found"
+	case "delete":
+		data.Results = "error"
+		data.SuccessMessage = data.HumanEntity + " deleted"
+	case "create":
+		data.Results = fmt.Sprintf("(*entity.%s, error)", entityName)
+		data.SuccessStatus = "fiber.StatusCreated"
+		data.SuccessMessage = data.HumanEntity + " created"
+	case "update":
+		data.Results = fmt.Sprintf("(*entity.%s, error)", entityName)
+		data.SuccessMessage = data.HumanEntity + " updated"
+	default:
+		data.Results = fmt.Sprintf("(*entity.%s, error)", entityName)
+		data.SuccessMessage = data.HumanEntity + " found"
+	}
+
+	return data, nil
+}
+
+// loadLayer finds the layer's interface, its implementation (the struct returned by the
+// interface's constructor) and the field through which the implementation reaches the next layer.
+func loadLayer(dir string, layer string) (*layerFile, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		ifaceName, iface := findLayerInterface(file, capitalize([]string{layer}))
+		if iface == nil {
+			continue
+		}
+
+		lf := &layerFile{layer: layer, path: path, src: src, fset: fset, file: file, iface: iface}
+
+		constructor := findFunc(file, "New"+ifaceName)
+		if constructor == nil {
+			return nil, fmt.Errorf("%s: constructor New%s not found", path, ifaceName)
+		}
+		lf.impl = returnedStruct(constructor)
+		if lf.impl == "" {
+			return nil, fmt.Errorf("%s: could not find the struct returned by New%s", path, ifaceName)
+		}
+
+		lf.recv = receiverName(file, lf.impl)
+		if lf.recv == "" {
+			lf.recv = layer[:1]
+		}
+
+		lf.dep = dependencyField(file, lf.impl, layer)
+		if lf.dep == "" {
+			return nil, fmt.Errorf("%s: could not find the dependency field of %s", path, lf.impl)
+		}
+
+		return lf, nil
+	}
+
+	return nil, fmt.Errorf("no %s interface found in %s", layer, dir)
+}
+
+// ensureMethodAbsent refuses to overwrite a method declared in the interface or on the implementation.
+func (lf *layerFile) ensureMethodAbsent(name string) error {
+	for _, field := range lf.iface.Methods.List {
+		for _, ident := range field.Names {
+			if ident.Name == name {
+				return fmt.Errorf("%s: %s already declares %s", lf.path, lf.layer, name)
+			}
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(filepath.Dir(lf.path), "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Recv != nil && fn.Name.Name == name && receiverType(fn) == lf.impl {
+				return fmt.Errorf("%s: method %s.%s already exists", path, lf.impl, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (lf *layerFile) addEndpoint(tmpl *template.Template, data EndpointData) (*fileChange, error) {
+	data.Receiver = lf.recv
+	data.Impl = lf.impl
+	data.Dependency = lf.dep
+
+	data.Params = data.UsecaseParams
+	if lf.layer == "repository" {
+		data.Params = data.RepoParams
+	}
+
+	var method bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&method, lf.layer, data); err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(method.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated %s method does not compile: %w", lf.layer, err)
+	}
+
+	signature := fmt.Sprintf("%s(%s) %s", data.Name, data.Params, data.Results)
+	if lf.layer == "handler" {
+		signature = data.Name + "(c *fiber.Ctx) error"
+	}
+
+	updated, err := splice(lf.src,
+		insertion{offset: lf.fset.Position(lf.iface.Methods.Closing).Offset, text: "\t" + signature + "\n"},
+		insertion{offset: len(lf.src), text: "\n" + strings.TrimSpace(string(formatted)) + "\n"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err = addImports(updated, lf.requiredImports(data)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileChange{Path: lf.path, Old: string(lf.src), New: string(updated)}, nil
+}
+
+func (lf *layerFile) requiredImports(data EndpointData) []importRef {
+	moduleName := filepath.Base(filepath.Dir(filepath.Dir(lf.path)))
+	entityImport := fmt.Sprintf("%s/internal/modules/%s/entity", goModulePath, moduleName)
+
+	dtoImport := fmt.Sprintf("%s/internal/modules/%s/dto", goModulePath, moduleName)
+
+	imports := []string{"context"}
+	for _, param := range data.PathParams {
+		if param.IsUUID {
+			imports = append(imports, "github.com/google/uuid")
+		}
+	}
+
+	switch lf.layer {
+	case "repository":
+		imports = append(imports, entityImport)
+		if data.Kind == "update" || data.Kind == "delete" {
+			imports = append(imports, "gorm.io/gorm")
+		}
+	case "usecase":
+		imports = append(imports, goModulePath+"/pkg/errorhandler")
+		if data.Kind != "delete" {
+			imports = append(imports, entityImport)
+		}
+		if data.HasBody {
+			imports = append(imports, dtoImport)
+		}
+	case "handler":
+		imports = append(imports, "time", "github.com/gofiber/fiber/v2", goModulePath+"/pkg/errorhandler", goModulePath+"/pkg/response")
+		if data.HasBody {
+			imports = append(imports, goModulePath+"/pkg/validation")
+		}
+		if data.HasBody || data.ResponseFunc != "" {
+			imports = append(imports, dtoImport)
+		}
+	}
+
+	refs := make([]importRef, 0, len(imports))
+	for _, path := range imports {
+		refs = append(refs, importRef{path: path})
+	}
+	return refs
+}
+
+// addEndpointDTO appends the endpoint's request DTO to the module's dto package and looks up the
+// function that maps the entity to its response DTO, if the package declares one.
+func addEndpointDTO(dir string, tmpl *template.Template, data *EndpointData) (*fileChange, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	target := ""
+	declared := map[string]bool{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		if target == "" || filepath.Base(path) == "dto.go" {
+			target = path
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for name, obj := range file.Scope.Objects {
+			if obj.Kind == ast.Typ || obj.Kind == ast.Fun {
+				declared[name] = true
+			}
+		}
+	}
+
+	responseFunc := "New" + data.Entity + "Response"
+	if data.Kind == "list" {
+		responseFunc += "s"
+	}
+	if declared[responseFunc] {
+		data.ResponseFunc = responseFunc
+	}
+
+	if !data.HasBody {
+		return nil, nil
+	}
+	if target == "" {
+		return nil, fmt.Errorf("no dto package found in %s for %s", dir, data.RequestType)
+	}
+	if declared[data.RequestType] {
+		return nil, fmt.Errorf("%s: dto %s already exists", dir, data.RequestType)
+	}
+
+	var decl bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&decl, "dto", data); err != nil {
+		return nil, err
+	}
+
+	return editFile(target, func(src []byte) ([]byte, error) {
+		return splice(src, insertion{offset: len(src), text: "\n" + strings.TrimSpace(decl.String()) + "\n"})
+	})
+}
+
+// registerEndpointRoute adds group.Verb(path, handler.Name) to the module's Init…Routes function.
+// Static paths are placed before the first parameterized route of the same verb so they are not shadowed.
+func registerEndpointRoute(src []byte, funcName string, verb string, path string, name string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	fn := findFunc(file, funcName)
+	if fn == nil || fn.Body == nil {
+		return nil, fmt.Errorf("function %s not found", funcName)
+	}
+
+	var group, handlerVar string
+	for _, stmt := range fn.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Group" && group == "" {
+			group = ident.Name
+		} else if strings.HasSuffix(ident.Name, "Handler") && handlerVar == "" {
+			handlerVar = ident.Name
+		}
+	}
+	if group == "" || handlerVar == "" {
+		return nil, fmt.Errorf("%s does not create a route group and handler", funcName)
+	}
+
+	var last, firstParam ast.Stmt
+	for _, stmt := range fn.Body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !routeVerbs[sel.Sel.Name] {
+			continue
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != group {
+			continue
+		}
+
+		routePath := ""
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			routePath, _ = strconv.Unquote(lit.Value)
+		}
+		if sel.Sel.Name == verb && routePath == path {
+			return nil, fmt.Errorf("route %s %s is already registered", strings.ToUpper(verb), path)
+		}
+
+		if sel.Sel.Name == verb && firstParam == nil && strings.Contains(routePath, ":") && !strings.Contains(path, ":") {
+			firstParam = stmt
+		}
+		last = stmt
+	}
+
+	registration := fmt.Sprintf("%s.%s(%q, %s.%s)", group, verb, path, handlerVar, name)
+
+	if firstParam != nil {
+		return splice(src, insertion{offset: lineStart(fset, firstParam.Pos()), text: "\t" + registration + "\n"})
+	}
+	if last != nil {
+		return splice(src, insertion{offset: fset.Position(last.End()).Offset, text: "\n\t" + registration})
+	}
+	return splice(src, insertion{offset: fset.Position(fn.Body.Rbrace).Offset, text: "\n\t" + registration + "\n"})
+}
+
+func findLayerInterface(file *ast.File, suffix string) (string, *ast.InterfaceType) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			iface, ok := typeSpec.Type.(*ast.InterfaceType)
+			if ok && typeSpec.Name.IsExported() && strings.HasSuffix(typeSpec.Name.Name, suffix) {
+				return typeSpec.Name.Name, iface
+			}
+		}
+	}
+	return "", nil
+}
+
+func returnedStruct(fn *ast.FuncDecl) string {
+	name := ""
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) == 0 {
+			return name == ""
+		}
+		if unary, ok := ret.Results[0].(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			if lit, ok := unary.X.(*ast.CompositeLit); ok {
+				if ident, ok := lit.Type.(*ast.Ident); ok {
+					name = ident.Name
+				}
+			}
+		}
+		return name == ""
+	})
+	return name
+}
+
+func receiverName(file *ast.File, impl string) string {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Recv != nil && receiverType(fn) == impl && len(fn.Recv.List[0].Names) > 0 {
+			return fn.Recv.List[0].Names[0].Name
+		}
+	}
+	return ""
+}
+
+func receiverType(fn *ast.FuncDecl) string {
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// dependencyField returns the field of impl that points to the layer below: the *gorm.DB for
+// repositories, otherwise the first field typed as a usecase or repository interface.
+func dependencyField(file *ast.File, impl string, layer string) string {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok || typeSpec.Name.Name != impl {
+				continue
+			}
+			for _, field := range structType.Fields.List {
+				expr := field.Type
+				if star, ok := expr.(*ast.StarExpr); ok {
+					expr = star.X
+				}
+				sel, ok := expr.(*ast.SelectorExpr)
+				if !ok || len(field.Names) == 0 {
+					continue
+				}
+				pkg, _ := sel.X.(*ast.Ident)
+				if pkg == nil {
+					continue
+				}
+
+				switch {
+				case layer == "repository" && pkg.Name == "gorm" && sel.Sel.Name == "DB":
+					return field.Names[0].Name
+				case layer != "repository" && (pkg.Name == "usecase" || pkg.Name == "repository"):
+					return field.Names[0].Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// firstEntity returns the first entity type mentioned in an interface's method results.
+func firstEntity(iface *ast.InterfaceType) string {
+	name := ""
+	ast.Inspect(iface, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "entity" {
+				name = sel.Sel.Name
+			}
+		}
+		return name == ""
+	})
+	return name
+}
+
+// splitCamel splits an identifier such as "UserProfile" or "userID" into its words.
+func splitCamel(s string) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(s); i++ {
+		lower := s[i-1] >= 'a' && s[i-1] <= 'z'
+		upper := s[i] >= 'A' && s[i] <= 'Z'
+		if lower && upper {
+			words = append(words, s[start:i])
+			start = i
+		}
+	}
+	return append(words, s[start:])
+}
@@ -1,194 +1,465 @@
 package cmd
 
 import (
+	"bytes"
+	"embed"
+	"errors"
 	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
 
 	"github.com/rs/zerolog/log"
 )
 
+const goModulePath = "github.com/revandpratama/lognest"
+
+//go:embed templates
+var generatorTemplates embed.FS
+
+var (
+	moduleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+	fieldNamePattern  = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+)
+
+// reservedFields are added to every generated entity and cannot be redeclared.
+var reservedFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+type fieldType struct {
+	GoType  string
+	SQLType string
+	// FilterType is the pagination.FilterType the field is filtered as, or empty when it cannot
+	// be filtered on.
+	FilterType string
+}
+
+var fieldTypes = map[string]fieldType{
+	"string": {GoType: "string", SQLType: "varchar(255)", FilterType: "String"},
+	"text":   {GoType: "string", SQLType: "text"},
+	"int":    {GoType: "int64", SQLType: "bigint", FilterType: "Int"},
+	"float":  {GoType: "float64", SQLType: "double precision", FilterType: "Float"},
+	"bool":   {GoType: "bool", SQLType: "boolean", FilterType: "Bool"},
+	"uuid":   {GoType: "uuid.UUID", SQLType: "uuid", FilterType: "UUID"},
+	"time":   {GoType: "time.Time", SQLType: "timestamptz", FilterType: "Time"},
+}
+
 type TemplateData struct {
 	OriginalModuleName    string
-	ModuleName            string 
-	CapitalizedModuleName string 
-	GoModulePath          string 
+	ModuleName            string
+	CapitalizedModuleName string
+	GoModulePath          string
+	HumanName             string
+	TableName             string
+	RoutePath             string
+	Fields                []Field
+	// Columns are the column definitions of the CREATE TABLE statement, aligned like the
+	// hand-written migrations.
+	Columns []string
 }
 
-func GenerateModule(moduleName string) {
-	const goModulePath = "github.com/revandpratama/lognest"
+// Field is one column of a generated entity, parsed from "name:type[:modifier...]".
+type Field struct {
+	Name        string
+	Column      string
+	JSON        string
+	GoType      string
+	SQLType     string
+	GormTag     string
+	ValidateTag string
+	NotNull     bool
+	Unique      bool
+	Index       bool
+	Sortable    bool
+	FilterType  string
+}
 
-	sanitizedModuleName := strings.ReplaceAll(moduleName, "-", "")
+// GenerateOptions configures GenerateModule.
+type GenerateOptions struct {
+	ModuleName string
+	Fields     []string
+	DryRun     bool
+}
 
-	// e.g., "user-profile" -> ["user", "profile"] -> ["User", "Profile"] -> "UserProfile"
-	parts := strings.Split(moduleName, "-")
-	var capitalizedParts []string
-	for _, part := range parts {
-		if len(part) > 0 {
-			capitalizedParts = append(capitalizedParts, strings.ToUpper(string(part[0]))+part[1:])
-		}
-	}
-	capitalizedModuleName := strings.Join(capitalizedParts, "")
+type generatedFile struct {
+	template string
+	path     string
+}
 
-	data := TemplateData{
-		OriginalModuleName:    moduleName,
-		ModuleName:            sanitizedModuleName,
-		CapitalizedModuleName: capitalizedModuleName,
-		GoModulePath:          goModulePath,
+// fileChange is a single file the generator wants to create or rewrite.
+type fileChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// GenerateModule scaffolds a CRUD module across every layer, wires its routes and model,
+// and adds a migration for its table. With DryRun set it only prints the resulting diff.
+func GenerateModule(opts GenerateOptions) error {
+	data, err := newTemplateData(opts.ModuleName, opts.Fields)
+	if err != nil {
+		return err
 	}
 
-	log.Info().Msgf("Generating module: %s\n", moduleName)
+	changes, err := planModule(data)
+	if err != nil {
+		return err
+	}
 
-	// Define the directory and file structure
-	structure := map[string]string{
-		"entity":     entityTemplate,
-		"repository": repositoryTemplate,
-		"usecase":    usecaseTemplate,
-		"handler":    handlerTemplate,
+	if len(changes) == 0 {
+		log.Info().Msgf("module %s is already up to date", opts.ModuleName)
+		return nil
 	}
 
-	for dir, fileTemplate := range structure {
-		// Create directory
-		fullDirPath := filepath.Join("internal", "modules", moduleName, dir)
-		if err := os.MkdirAll(fullDirPath, os.ModePerm); err != nil {
-			log.Fatal().Err(err).Msgf("Failed to create directory %s: %v", fullDirPath, err)
+	if opts.DryRun {
+		for _, change := range changes {
+			fmt.Print(unifiedDiff(change.Path, change.Old, change.New))
 		}
+		return nil
+	}
 
-		// Create file
-		fileName := fmt.Sprintf("%s.go", dir)
-		fullFilePath := filepath.Join(fullDirPath, fileName)
-		if err := createFileFromTemplate(fullFilePath, fileTemplate, data); err != nil {
-			log.Fatal().Err(err).Msgf("Failed to create file %s: %v", fullFilePath, err)
-		}
-		log.Printf("  ✓ Created %s", fullFilePath)
+	log.Info().Msgf("Generating module: %s", opts.ModuleName)
+
+	if err := applyChanges(changes); err != nil {
+		return err
 	}
 
-	log.Info().Msg("\nModule generation complete! ✨")
+	log.Info().Msg("Module generation complete! ✨")
+	return nil
 }
 
-func createFileFromTemplate(path, tmpl string, data TemplateData) error {
-	// Check if file already exists
-	if _, err := os.Stat(path); !os.IsNotExist(err) {
-		log.Printf("  ! Skipped %s (already exists)", path)
-		return nil
+func newTemplateData(moduleName string, specs []string) (TemplateData, error) {
+	if !moduleNamePattern.MatchString(moduleName) {
+		return TemplateData{}, fmt.Errorf("invalid module name %q: use lowercase words separated by dashes, e.g. user-profile", moduleName)
 	}
 
-	file, err := os.Create(path)
+	// e.g., "user-profile" -> ["user", "profile"] -> ["User", "Profile"] -> "UserProfile"
+	parts := strings.Split(moduleName, "-")
+
+	fields, err := parseFields(specs)
 	if err != nil {
-		return err
+		return TemplateData{}, err
 	}
-	defer file.Close()
 
-	t, err := template.New("file").Parse(tmpl)
-	if err != nil {
-		return err
+	data := TemplateData{
+		OriginalModuleName:    moduleName,
+		ModuleName:            strings.Join(parts, ""),
+		CapitalizedModuleName: capitalize(parts),
+		GoModulePath:          goModulePath,
+		HumanName:             strings.Join(parts, " "),
+		TableName:             strings.Join(parts, "_") + "s",
+		RoutePath:             moduleName + "s",
+		Fields:                fields,
+		Columns:               sqlColumns(fields),
 	}
 
-	return t.Execute(file, data)
+	return data, nil
 }
 
-const entityTemplate = `package entity
+// sqlColumns renders the id, field and timestamp columns with their names and types padded to a
+// common width.
+func sqlColumns(fields []Field) []string {
+	type column struct{ name, sqlType, constraint string }
 
-import (
-	"fmt"
-	"time"
-
-	"github.com/google/uuid"
-	"github.com/revandpratama/lognest/config"
-	"gorm.io/gorm"
-)
+	columns := []column{{"id", "uuid", "NOT NULL"}}
+	for _, field := range fields {
+		constraint := ""
+		if field.NotNull {
+			constraint = "NOT NULL"
+		}
+		columns = append(columns, column{field.Column, field.SQLType, constraint})
+	}
+	columns = append(columns,
+		column{"created_at", "timestamptz", "NOT NULL"},
+		column{"updated_at", "timestamptz", "NOT NULL"},
+		column{"deleted_at", "timestamptz", ""},
+	)
+
+	nameWidth, typeWidth := 0, 0
+	for _, c := range columns {
+		nameWidth = max(nameWidth, len(c.name))
+		typeWidth = max(typeWidth, len(c.sqlType))
+	}
 
-// {{.CapitalizedModuleName}} represents the data structure for a {{.ModuleName}}.
-type {{.CapitalizedModuleName}} struct {
-	ID          uuid.UUID      ` + "`" + `gorm:"type:uuid;primary_key" json:"id"` + "`" + `
-	CreatedAt   time.Time      ` + "`" + `gorm:"not null" json:"created_at"` + "`" + `
-	UpdatedAt   time.Time      ` + "`" + `gorm:"not null" json:"updated_at"` + "`" + `
-	DeletedAt   gorm.DeletedAt ` + "`" + `gorm:"index" json:"-"` + "`" + `
+	lines := make([]string, 0, len(columns))
+	for _, c := range columns {
+		if c.constraint == "" {
+			lines = append(lines, fmt.Sprintf("%-*s %s", nameWidth, c.name, c.sqlType))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%-*s %-*s %s", nameWidth, c.name, typeWidth, c.sqlType, c.constraint))
+	}
+	return lines
 }
 
-// TableName sets the table name for the {{.CapitalizedModuleName}}.
-func ({{.CapitalizedModuleName}}) TableName() string {
-	return fmt.Sprintf("%s.%s", config.ENV.LOGNEST_SCHEMA, "{{.ModuleName}}s")
-}
+// parseFields turns specs such as "slug:string:unique:required" into Fields.
+// Supported modifiers are required, unique and index.
+func parseFields(specs []string) ([]Field, error) {
+	seen := map[string]bool{}
 
-func (p *{{.CapitalizedModuleName}}) BeforeCreate(tx *gorm.DB) error {
-	if p.ID == uuid.Nil {
-		uuidGenerated, err := uuid.NewV7()
-		if err != nil {
-			return err
+	fields := make([]Field, 0, len(specs))
+	for _, spec := range specs {
+		segments := strings.Split(spec, ":")
+		if len(segments) < 2 {
+			return nil, fmt.Errorf("invalid field %q: expected name:type[:modifier...]", spec)
 		}
-		p.ID = uuidGenerated
-	}
-	return nil
-}
-`
 
-const repositoryTemplate = `package repository
+		name, typeName := segments[0], segments[1]
+		if !fieldNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid field name %q: use snake_case", name)
+		}
+		if reservedFields[name] {
+			return nil, fmt.Errorf("field %q is added to every module and cannot be redeclared", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("field %q is declared more than once", name)
+		}
+		seen[name] = true
 
-import (
-	"gorm.io/gorm"
-)
+		ft, ok := fieldTypes[typeName]
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %q for field %q", typeName, name)
+		}
 
-// {{.CapitalizedModuleName}}Repository defines the interface for database operations for a {{.CapitalizedModuleName}}.
-type {{.CapitalizedModuleName}}Repository interface {
-	
-}
+		field := Field{
+			Name:       goFieldName(name),
+			Column:     name,
+			JSON:       name,
+			GoType:     ft.GoType,
+			SQLType:    ft.SQLType,
+			Sortable:   typeName != "text" && typeName != "bool",
+			FilterType: ft.FilterType,
+		}
 
-type {{.ModuleName}}Repository struct {
-	db *gorm.DB
-}
+		for _, modifier := range segments[2:] {
+			switch modifier {
+			case "required":
+				field.NotNull = true
+			case "unique":
+				field.Unique = true
+			case "index":
+				field.Index = true
+			default:
+				return nil, fmt.Errorf("unsupported modifier %q for field %q", modifier, name)
+			}
+		}
+
+		// time.Time has no zero value that maps to NULL.
+		if typeName == "time" && !field.NotNull {
+			field.GoType = "*time.Time"
+		}
+
+		var rules []string
+		switch typeName {
+		case "string":
+			rules = append(rules, "max=255")
+		case "uuid":
+			rules = append(rules, "uuid")
+		}
+
+		gormTag := []string{"type:" + ft.SQLType}
+		if field.NotNull {
+			gormTag = append(gormTag, "not null")
+			field.ValidateTag = strings.Join(append([]string{"required"}, rules...), ",")
+		} else if len(rules) > 0 {
+			field.ValidateTag = strings.Join(append([]string{"omitempty"}, rules...), ",")
+		}
+		if field.Unique {
+			gormTag = append(gormTag, "uniqueIndex")
+		} else if field.Index {
+			gormTag = append(gormTag, "index")
+		}
+		field.GormTag = strings.Join(gormTag, ";")
 
-// New{{.CapitalizedModuleName}}Repository creates a new instance of {{.CapitalizedModuleName}}Repository.
-func New{{.CapitalizedModuleName}}Repository(db *gorm.DB) {{.CapitalizedModuleName}}Repository {
-	return &{{.ModuleName}}Repository{db: db}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
 }
 
-// NOTE: The following are example implementations. You will need to adjust them.
-`
+// planModule renders every generated file and the edits to existing files, skipping
+// files that already exist so re-running the generator never clobbers hand-written code.
+func planModule(data TemplateData) ([]fileChange, error) {
+	moduleDir := filepath.Join("internal", "modules", data.OriginalModuleName)
+
+	files := []generatedFile{
+		{"entity.go.tmpl", filepath.Join(moduleDir, "entity", "entity.go")},
+		{"dto.go.tmpl", filepath.Join(moduleDir, "dto", "dto.go")},
+		{"repository.go.tmpl", filepath.Join(moduleDir, "repository", "repository.go")},
+		{"usecase.go.tmpl", filepath.Join(moduleDir, "usecase", "usecase.go")},
+		{"usecase_test.go.tmpl", filepath.Join(moduleDir, "usecase", "usecase_test.go")},
+		{"handler.go.tmpl", filepath.Join(moduleDir, "handler", "handler.go")},
+		{"route.go.tmpl", filepath.Join("internal", "routes", data.OriginalModuleName+"_route.go")},
+	}
 
-const usecaseTemplate = `package usecase
+	migrationName := "create_" + data.TableName
+	migrationExists, err := hasMigration(migrationName)
+	if err != nil {
+		return nil, err
+	}
+	if !migrationExists {
+		version := newMigrationVersion()
+		files = append(files,
+			generatedFile{"migration.up.sql.tmpl", migrationPath(version, migrationName, "up")},
+			generatedFile{"migration.down.sql.tmpl", migrationPath(version, migrationName, "down")},
+		)
+	}
 
-import (
-	"{{.GoModulePath}}/internal/modules/{{.OriginalModuleName}}/repository"
-)
+	var changes []fileChange
+	for _, file := range files {
+		if _, err := os.Stat(file.path); err == nil {
+			log.Info().Msgf("  ! Skipped %s (already exists)", file.path)
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
 
-// {{.CapitalizedModuleName}}Usecase defines the business logic interface for a {{.CapitalizedModuleName}}.
-type {{.CapitalizedModuleName}}Usecase interface {
+		content, err := renderTemplate(file.template, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", file.path, err)
+		}
+		changes = append(changes, fileChange{Path: file.path, New: content})
+	}
 
-}
+	routeFile := filepath.Join("internal", "routes", "route.go")
+	routeChange, err := editFile(routeFile, func(src []byte) ([]byte, error) {
+		return registerRoute(src, fmt.Sprintf("Init%sRoutes(api, db, authMiddleware)", data.CapitalizedModuleName))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if routeChange != nil {
+		changes = append(changes, *routeChange)
+	}
 
-type {{.ModuleName}}Usecase struct {
-	repo repository.{{.CapitalizedModuleName}}Repository
+	migrateFile := filepath.Join("cmd", "migrate.go")
+	modelChange, err := editFile(migrateFile, func(src []byte) ([]byte, error) {
+		return registerModel(src,
+			lowerCamel(strings.Split(data.OriginalModuleName, "-"))+"Entity",
+			fmt.Sprintf("%s/internal/modules/%s/entity", goModulePath, data.OriginalModuleName),
+			data.CapitalizedModuleName,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if modelChange != nil {
+		changes = append(changes, *modelChange)
+	}
+
+	return changes, nil
 }
 
-// New{{.CapitalizedModuleName}}Usecase creates a new instance of {{.CapitalizedModuleName}}Usecase.
-func New{{.CapitalizedModuleName}}Usecase(repo repository.{{.CapitalizedModuleName}}Repository) {{.CapitalizedModuleName}}Usecase {
-	return &{{.ModuleName}}Usecase{repo: repo}
+func renderTemplate(name string, data TemplateData) (string, error) {
+	t, err := template.ParseFS(generatorTemplates, "templates/module/"+name)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return buf.String(), nil
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("generated code does not compile: %w", err)
+	}
+	return string(formatted), nil
 }
 
+// editFile applies edit to path and returns the change, or nil when the file is already up to date.
+func editFile(path string, edit func(src []byte) ([]byte, error)) (*fileChange, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 
-`
+	updated, err := edit(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", path, err)
+	}
 
-const handlerTemplate = `package handler
+	if bytes.Equal(src, updated) {
+		return nil, nil
+	}
 
-import (
-	"{{.GoModulePath}}/internal/modules/{{.OriginalModuleName}}/usecase"
-)
+	return &fileChange{Path: path, Old: string(src), New: string(updated)}, nil
+}
+
+func applyChanges(changes []fileChange) error {
+	for _, change := range changes {
+		if err := os.MkdirAll(filepath.Dir(change.Path), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(change.Path, []byte(change.New), 0o644); err != nil {
+			return err
+		}
+
+		if change.Old == "" {
+			log.Info().Msgf("  ✓ Created %s", change.Path)
+		} else {
+			log.Info().Msgf("  ✓ Updated %s", change.Path)
+		}
+	}
+	return nil
+}
 
-// {{.CapitalizedModuleName}}Handler defines the HTTP handler interface for a {{.CapitalizedModuleName}}.
-type {{.CapitalizedModuleName}}Handler interface {
-	// TODO: Add other handler methods (FindByUserID, FindBySlug, etc.)
+func hasMigration(name string) (bool, error) {
+	matches, err := filepath.Glob(filepath.Join(MigrationsDir, "*_"+name+".up.sql"))
+	if err != nil {
+		return false, err
+	}
+	return len(matches) > 0, nil
 }
 
-type {{.ModuleName}}Handler struct {
-	usecase usecase.{{.CapitalizedModuleName}}Usecase
+func capitalize(parts []string) string {
+	var capitalizedParts []string
+	for _, part := range parts {
+		if len(part) > 0 {
+			capitalizedParts = append(capitalizedParts, strings.ToUpper(part[:1])+part[1:])
+		}
+	}
+	return strings.Join(capitalizedParts, "")
+}
+
+// commonInitialisms keeps generated field names in line with Go naming, e.g. user_id -> UserID.
+var commonInitialisms = map[string]string{
+	"id":   "ID",
+	"url":  "URL",
+	"uri":  "URI",
+	"ip":   "IP",
+	"api":  "API",
+	"http": "HTTP",
+	"json": "JSON",
+	"uuid": "UUID",
 }
 
-// New{{.CapitalizedModuleName}}Handler creates a new instance of {{.CapitalizedModuleName}}Handler.
-func New{{.CapitalizedModuleName}}Handler(usecase usecase.{{.CapitalizedModuleName}}Usecase) {{.CapitalizedModuleName}}Handler {
-	return &{{.ModuleName}}Handler{usecase: usecase}
+func goFieldName(column string) string {
+	parts := strings.Split(column, "_")
+	for i, part := range parts {
+		if initialism, ok := commonInitialisms[part]; ok {
+			parts[i] = initialism
+		}
+	}
+	return capitalize(parts)
 }
 
-`
+func lowerCamel(parts []string) string {
+	camel := capitalize(parts)
+	if camel == "" {
+		return camel
+	}
+	return strings.ToLower(camel[:1]) + camel[1:]
+}
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The generator locates insertion points through the AST and splices text at those offsets,
+// so comments and blank lines in hand-maintained files survive the rewrite.
+
+type insertion struct {
+	offset int
+	text   string
+}
+
+// registerRoute appends call to the body of InitRoutes unless a call to the same function is already there.
+func registerRoute(src []byte, call string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	callExpr, err := parser.ParseExpr(call)
+	if err != nil {
+		return nil, fmt.Errorf("invalid route call %q: %w", call, err)
+	}
+	funcName := callName(callExpr)
+
+	initRoutes := findFunc(file, "InitRoutes")
+	if initRoutes == nil || initRoutes.Body == nil {
+		return nil, fmt.Errorf("function InitRoutes not found")
+	}
+
+	registered := false
+	ast.Inspect(initRoutes.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && callName(call) == funcName {
+			registered = true
+		}
+		return !registered
+	})
+	if registered {
+		return src, nil
+	}
+
+	return splice(src, insertion{
+		offset: fset.Position(initRoutes.Body.Rbrace).Offset,
+		text:   "\n\t" + call + "\n",
+	})
+}
+
+// registerModel adds &alias.typeName{} to the models registry, importing importPath as alias when needed.
+func registerModel(src []byte, alias string, importPath string, typeName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	importName, imported := importedAs(file, importPath)
+	if !imported {
+		importName = alias
+	}
+
+	models := findVarCompositeLit(file, "models")
+	if models == nil {
+		return nil, fmt.Errorf("models registry not found")
+	}
+
+	for _, elt := range models.Elts {
+		if isModelRef(elt, importName, typeName) {
+			return src, nil
+		}
+	}
+
+	// The import block precedes the registry, so adding the model first keeps the import offsets valid.
+	updated, err := splice(src, insertion{
+		offset: fset.Position(models.Rbrace).Offset,
+		text:   fmt.Sprintf("\t&%s.%s{},\n", importName, typeName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return addImports(updated, importRef{name: alias, path: importPath})
+}
+
+type importRef struct {
+	name string
+	path string
+}
+
+// addImports adds the missing imports to the file's import block, keeping the standard library
+// group first and each group sorted, as goimports would.
+func addImports(src []byte, refs ...importRef) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	importDecl := findImportBlock(file)
+	if importDecl == nil {
+		return nil, fmt.Errorf("no parenthesized import block found")
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].path < refs[j].path
+	})
+
+	var insertions []insertion
+	added := map[string]bool{}
+	for _, ref := range refs {
+		if _, ok := importedAs(file, ref.path); ok || added[ref.path] {
+			continue
+		}
+		added[ref.path] = true
+
+		line := fmt.Sprintf("\t%q\n", ref.path)
+		if ref.name != "" {
+			line = fmt.Sprintf("\t%s %q\n", ref.name, ref.path)
+		}
+
+		offset, newGroup := importOffset(fset, importDecl, ref.path)
+		if n := len(insertions); n > 0 && insertions[n-1].offset == offset {
+			insertions[n-1].text = joinImportLines(insertions[n-1].text, line, newGroup, isStdlibImport(ref.path))
+			continue
+		}
+		insertions = append(insertions, insertion{offset: offset, text: joinImportLines("", line, newGroup, isStdlibImport(ref.path))})
+	}
+
+	if len(insertions) == 0 {
+		return src, nil
+	}
+
+	sort.SliceStable(insertions, func(i, j int) bool {
+		return insertions[i].offset < insertions[j].offset
+	})
+
+	return splice(src, insertions...)
+}
+
+// importOffset returns where path belongs: before the first import of its group that sorts after it,
+// after the last import of its group, or in a new group when the file has none of its kind.
+func importOffset(fset *token.FileSet, decl *ast.GenDecl, path string) (int, bool) {
+	std := isStdlibImport(path)
+
+	var lastInGroup *ast.ImportSpec
+	for _, spec := range decl.Specs {
+		importSpec := spec.(*ast.ImportSpec)
+		specPath, _ := strconv.Unquote(importSpec.Path.Value)
+		if isStdlibImport(specPath) != std {
+			continue
+		}
+		if specPath > path {
+			return lineStart(fset, importSpec.Pos()), false
+		}
+		lastInGroup = importSpec
+	}
+
+	switch {
+	case lastInGroup != nil:
+		return nextLineStart(fset, lastInGroup.End()), false
+	case std && len(decl.Specs) > 0:
+		return lineStart(fset, decl.Specs[0].Pos()), true
+	case len(decl.Specs) > 0:
+		return fset.Position(decl.Rparen).Offset, true
+	}
+	return fset.Position(decl.Rparen).Offset, false
+}
+
+// joinImportLines appends line to the text inserted at one offset, separating a new group with a blank line.
+func joinImportLines(text string, line string, newGroup bool, std bool) string {
+	if !newGroup {
+		return text + line
+	}
+	if std {
+		return strings.TrimSuffix(text, "\n") + line + "\n"
+	}
+	if text == "" {
+		return "\n" + line
+	}
+	return text + line
+}
+
+func isStdlibImport(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func lineStart(fset *token.FileSet, pos token.Pos) int {
+	position := fset.Position(pos)
+	return position.Offset - (position.Column - 1)
+}
+
+func nextLineStart(fset *token.FileSet, pos token.Pos) int {
+	file := fset.File(pos)
+	line := file.Line(pos)
+	if line >= file.LineCount() {
+		return file.Size()
+	}
+	return file.Offset(file.LineStart(line + 1))
+}
+
+// splice applies insertions back to front so earlier offsets stay valid. The surrounding code is
+// left byte-for-byte untouched; the result is only parsed to make sure the edit kept it valid Go.
+func splice(src []byte, insertions ...insertion) ([]byte, error) {
+	out := append([]byte(nil), src...)
+	for i := len(insertions) - 1; i >= 0; i-- {
+		ins := insertions[i]
+		out = append(out[:ins.offset], append([]byte(ins.text), out[ins.offset:]...)...)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "", out, 0); err != nil {
+		return nil, fmt.Errorf("rewritten source does not parse: %w", err)
+	}
+	return out, nil
+}
+
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+func findImportBlock(file *ast.File) *ast.GenDecl {
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && gen.Lparen.IsValid() {
+			return gen
+		}
+	}
+	return nil
+}
+
+func findVarCompositeLit(file *ast.File, name string) *ast.CompositeLit {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for i, ident := range valueSpec.Names {
+				if ident.Name != name || i >= len(valueSpec.Values) {
+					continue
+				}
+				if lit, ok := valueSpec.Values[i].(*ast.CompositeLit); ok {
+					return lit
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// importedAs reports the name under which importPath is imported in file.
+func importedAs(file *ast.File, importPath string) (string, bool) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != importPath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name, true
+		}
+		return defaultImportName(path), true
+	}
+	return "", false
+}
+
+func defaultImportName(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return path[i+1:]
+		}
+	}
+	return path
+}
+
+func isModelRef(expr ast.Expr, pkg string, typeName string) bool {
+	unary, ok := expr.(*ast.UnaryExpr)
+	if !ok || unary.Op != token.AND {
+		return false
+	}
+	lit, ok := unary.X.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	sel, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg && sel.Sel.Name == typeName
+}
+
+func callName(expr ast.Expr) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return fn.Name
+	case *ast.SelectorExpr:
+		return fn.Sel.Name
+	}
+	return ""
+}
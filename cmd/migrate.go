@@ -1,49 +1,376 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/revandpratama/lognest/config"
+	authEntity "github.com/revandpratama/lognest/internal/modules/auth/entity"
 	interactionEntity "github.com/revandpratama/lognest/internal/modules/interaction/entity"
 	logEntity "github.com/revandpratama/lognest/internal/modules/log/entity"
 	projectEntity "github.com/revandpratama/lognest/internal/modules/project/entity"
 	tagEntity "github.com/revandpratama/lognest/internal/modules/tag/entity"
 	userProfileEntity "github.com/revandpratama/lognest/internal/modules/user-profile/entity"
+	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
-func EnsureSchema(db *gorm.DB, schema string) error {
-	return db.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema)).Error
-}
+// MigrationsDir is where migration files live in the source tree; they are embedded at build time.
+const MigrationsDir = "cmd/migrations"
+
+// migrationLockID is the Postgres advisory lock key that serializes concurrent migration runs.
+const migrationLockID = 7_020_331
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
 
+// models lists every entity persisted by lognest; the schema itself is owned by the SQL migrations.
 var models = []interface{}{
 	&projectEntity.Project{},
 	&tagEntity.Tag{},
 	&logEntity.Log{},
+	&logEntity.Media{},
 	&userProfileEntity.UserProfile{},
 	&interactionEntity.Comment{},
 	&interactionEntity.Like{},
+	&authEntity.Session{},
+}
+
+type Migration struct {
+	Version  int64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	Checksum  string    `gorm:"type:varchar(64);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return fmt.Sprintf("%s.%s", config.ENV.LOGNEST_SCHEMA, "schema_migrations")
+}
+
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+	Modified  bool
+}
+
+func EnsureSchema(db *gorm.DB, schema string) error {
+	return db.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema)).Error
+}
+
+// LoadMigrations reads the embedded migration files ordered by version.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		matches := migrationFileName.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		content, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %q and %q", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.UpSQL = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// MigrateUp applies every pending migration, each in its own transaction.
+func MigrateUp(db *gorm.DB) error {
+	return withMigrationLock(db, func(conn *gorm.DB) error {
+		migrations, applied, err := loadState(conn)
+		if err != nil {
+			return err
+		}
+
+		if err := verifyChecksums(migrations, applied); err != nil {
+			return err
+		}
+
+		pending := 0
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			log.Info().Msgf("applying migration %d_%s", migration.Version, migration.Name)
+
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(renderSQL(migration.UpSQL)).Error; err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					Checksum:  migration.Checksum,
+					AppliedAt: time.Now(),
+				}).Error
+			}); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			pending++
+		}
+
+		if pending == 0 {
+			log.Info().Msg("database is up to date")
+		}
+
+		return nil
+	})
+}
+
+// MigrateDown rolls back the last steps applied migrations, newest first.
+func MigrateDown(db *gorm.DB, steps int) error {
+	if steps <= 0 {
+		return errors.New("number of migrations to roll back must be positive")
+	}
+
+	return withMigrationLock(db, func(conn *gorm.DB) error {
+		migrations, applied, err := loadState(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			if migration.DownSQL == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+
+			log.Info().Msgf("rolling back migration %d_%s", migration.Version, migration.Name)
+
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(renderSQL(migration.DownSQL)).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+			}); err != nil {
+				return fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			steps--
+		}
+
+		return nil
+	})
 }
 
+// MigrationStatus reports every known migration and whether it has been applied.
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	migrations, applied, err := loadState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, migration := range migrations {
+		state := MigrationState{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			state.AppliedAt = &appliedAt
+			state.Modified = record.Checksum != migration.Checksum
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+// MigrateDatabase brings the schema up to date.
 func MigrateDatabase(db *gorm.DB) error {
+	return MigrateUp(db)
+}
 
-	if err := db.AutoMigrate(models...); err != nil {
+// MigrateDatabaseFresh rolls back every applied migration and re-applies them all.
+func MigrateDatabaseFresh(db *gorm.DB) error {
+	states, err := MigrationStatus(db)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	applied := 0
+	for _, state := range states {
+		if state.AppliedAt != nil {
+			applied++
+		}
+	}
+
+	if applied > 0 {
+		if err := MigrateDown(db, applied); err != nil {
+			return err
+		}
+	}
+
+	return MigrateUp(db)
 }
 
-func MigrateDatabaseFresh(db *gorm.DB) error {
+// CreateMigration writes an empty up/down pair named after the current UTC timestamp.
+func CreateMigration(name string) ([]string, error) {
+	name, err := normalizeMigrationName(name)
+	if err != nil {
+		return nil, err
+	}
 
-	var conjunctionTables = []interface{}{
-		&projectEntity.ProjectTag{},
+	if err := os.MkdirAll(MigrationsDir, os.ModePerm); err != nil {
+		return nil, err
 	}
 
-	models = append(models, conjunctionTables...)
+	version := newMigrationVersion()
 
-	if err := db.Migrator().DropTable(models...); err != nil {
-		return err
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := migrationPath(version, name, direction)
+		content := fmt.Sprintf("-- %s migration for %s.\n-- Use {{schema}} for the lognest schema, e.g. {{schema}}.projects.\n", direction, name)
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+func normalizeMigrationName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", errors.New("migration name is required")
 	}
+	return name, nil
+}
+
+func newMigrationVersion() string {
+	return time.Now().UTC().Format("20060102150405")
+}
+
+func migrationPath(version string, name string, direction string) string {
+	return filepath.Join(MigrationsDir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+}
+
+func loadState(db *gorm.DB) ([]Migration, map[int64]SchemaMigration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version    bigint       NOT NULL PRIMARY KEY,
+		name       varchar(255) NOT NULL,
+		checksum   varchar(64)  NOT NULL,
+		applied_at timestamptz  NOT NULL
+	)`, SchemaMigration{}.TableName())).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var records []SchemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, nil, err
+	}
+
+	applied := make(map[int64]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return migrations, applied, nil
+}
+
+func verifyChecksums(migrations []Migration, applied map[int64]SchemaMigration) error {
+	known := make(map[int64]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+
+		record, ok := applied[migration.Version]
+		if ok && record.Checksum != migration.Checksum {
+			return fmt.Errorf("migration %d_%s was modified after being applied; create a new migration instead", migration.Version, migration.Name)
+		}
+	}
+
+	for version, record := range applied {
+		if !known[version] {
+			return fmt.Errorf("database has migration %d_%s applied which is missing from this build", version, record.Name)
+		}
+	}
+
+	return nil
+}
+
+func withMigrationLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	// Advisory locks belong to a connection, so pin one for the whole run.
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID)
+
+		return fn(conn)
+	})
+}
 
-	return MigrateDatabase(db)
+func renderSQL(sql string) string {
+	return strings.ReplaceAll(sql, "{{schema}}", config.ENV.LOGNEST_SCHEMA)
 }
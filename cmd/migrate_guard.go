@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/revandpratama/lognest/config"
+)
+
+// ConfirmDestructiveMigration guards operations that drop data. Outside production it is a no-op;
+// in production it requires both the override flag and the database name typed back on stdin.
+func ConfirmDestructiveMigration(action string, force bool, in io.Reader, out io.Writer) error {
+	if config.ENV.APP_ENV != "production" {
+		return nil
+	}
+
+	if !force {
+		return fmt.Errorf("refusing to %s while APP_ENV=production; re-run with --force-production to override", action)
+	}
+
+	fmt.Fprintf(out, "WARNING: you are about to %s on the production database %q (schema %q).\n", action, config.ENV.DB_NAME, config.ENV.LOGNEST_SCHEMA)
+	fmt.Fprint(out, "This cannot be undone. Type the database name to confirm: ")
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	if strings.TrimSpace(answer) != config.ENV.DB_NAME {
+		return fmt.Errorf("confirmation did not match database name, aborting %s", action)
+	}
+
+	return nil
+}
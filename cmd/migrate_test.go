@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrationsChecksums(t *testing.T) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	for i, migration := range migrations {
+		if i > 0 && migration.Version <= migrations[i-1].Version {
+			t.Errorf("migration %d is not after %d", migration.Version, migrations[i-1].Version)
+		}
+
+		// The checksum covers only the up file, so fixing a down file does not block deploys.
+		sum := sha256.Sum256([]byte(migration.UpSQL))
+		if want := hex.EncodeToString(sum[:]); migration.Checksum != want {
+			t.Errorf("migration %d_%s checksum = %s, want %s", migration.Version, migration.Name, migration.Checksum, want)
+		}
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "create_users", Checksum: "aaa"},
+		{Version: 2, Name: "create_projects", Checksum: "bbb"},
+	}
+
+	tests := []struct {
+		name    string
+		applied map[int64]SchemaMigration
+		wantErr string
+	}{
+		{
+			name:    "nothing applied",
+			applied: map[int64]SchemaMigration{},
+		},
+		{
+			name:    "some pending",
+			applied: map[int64]SchemaMigration{1: {Version: 1, Name: "create_users", Checksum: "aaa"}},
+		},
+		{
+			name: "all applied",
+			applied: map[int64]SchemaMigration{
+				1: {Version: 1, Name: "create_users", Checksum: "aaa"},
+				2: {Version: 2, Name: "create_projects", Checksum: "bbb"},
+			},
+		},
+		{
+			name: "modified after being applied",
+			applied: map[int64]SchemaMigration{
+				1: {Version: 1, Name: "create_users", Checksum: "aaa"},
+				2: {Version: 2, Name: "create_projects", Checksum: "changed"},
+			},
+			wantErr: "2_create_projects was modified",
+		},
+		{
+			name: "applied but missing from the build",
+			applied: map[int64]SchemaMigration{
+				1: {Version: 1, Name: "create_users", Checksum: "aaa"},
+				3: {Version: 3, Name: "create_tags", Checksum: "ccc"},
+			},
+			wantErr: "3_create_tags applied which is missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyChecksums(migrations, tt.applied)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("verifyChecksums: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("verifyChecksums error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
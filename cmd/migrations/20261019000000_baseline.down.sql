@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS {{schema}}.sessions;
+DROP TABLE IF EXISTS {{schema}}.likes;
+DROP TABLE IF EXISTS {{schema}}.comments;
+DROP TABLE IF EXISTS {{schema}}.media;
+DROP TABLE IF EXISTS {{schema}}.logs;
+DROP TABLE IF EXISTS {{schema}}.project_tags;
+DROP TABLE IF EXISTS {{schema}}.tags;
+DROP TABLE IF EXISTS {{schema}}.projects;
+DROP TABLE IF EXISTS {{schema}}.user_followers;
+DROP TABLE IF EXISTS {{schema}}.user_profiles;
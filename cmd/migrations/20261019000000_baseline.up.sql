@@ -0,0 +1,121 @@
+-- Baseline schema matching the entities previously created by GORM AutoMigrate.
+-- Every statement is idempotent so databases created by AutoMigrate can adopt it as-is.
+
+CREATE TABLE IF NOT EXISTS {{schema}}.user_profiles (
+    user_id         uuid         NOT NULL,
+    bio             text,
+    email           text         NOT NULL,
+    first_name      varchar(255),
+    last_name       varchar(255),
+    avatar_path     varchar(500),
+    follower_count  bigint       DEFAULT 0,
+    following_count bigint       DEFAULT 0,
+    created_at      timestamptz  NOT NULL,
+    updated_at      timestamptz  NOT NULL,
+    deleted_at      timestamptz,
+    CONSTRAINT user_profiles_pkey PRIMARY KEY (user_id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_{{schema}}_user_profiles_email ON {{schema}}.user_profiles (email);
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_user_profiles_deleted_at ON {{schema}}.user_profiles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.user_followers (
+    follower_id  uuid NOT NULL,
+    following_id uuid NOT NULL,
+    CONSTRAINT user_followers_pkey PRIMARY KEY (follower_id, following_id),
+    CONSTRAINT fk_{{schema}}_user_followers_follower FOREIGN KEY (follower_id) REFERENCES {{schema}}.user_profiles (user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_{{schema}}_user_followers_following FOREIGN KEY (following_id) REFERENCES {{schema}}.user_profiles (user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.projects (
+    id               uuid         NOT NULL,
+    user_profile_id  uuid         NOT NULL,
+    title            varchar(255) NOT NULL,
+    description      text,
+    slug             varchar(255) NOT NULL,
+    cover_image_path varchar(255),
+    is_public        boolean      DEFAULT true,
+    created_at       timestamptz  NOT NULL,
+    updated_at       timestamptz  NOT NULL,
+    deleted_at       timestamptz,
+    CONSTRAINT projects_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_{{schema}}_projects_user_profile FOREIGN KEY (user_profile_id) REFERENCES {{schema}}.user_profiles (user_id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_{{schema}}_projects_slug ON {{schema}}.projects (slug);
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_projects_deleted_at ON {{schema}}.projects (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.tags (
+    id         uuid         NOT NULL,
+    name       varchar(255) NOT NULL,
+    created_at timestamptz  NOT NULL,
+    updated_at timestamptz  NOT NULL,
+    deleted_at timestamptz,
+    CONSTRAINT tags_pkey PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_tags_deleted_at ON {{schema}}.tags (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.project_tags (
+    project_id uuid NOT NULL,
+    tag_id     uuid NOT NULL,
+    CONSTRAINT project_tags_pkey PRIMARY KEY (project_id, tag_id),
+    CONSTRAINT fk_{{schema}}_project_tags_project FOREIGN KEY (project_id) REFERENCES {{schema}}.projects (id) ON DELETE CASCADE,
+    CONSTRAINT fk_{{schema}}_project_tags_tag FOREIGN KEY (tag_id) REFERENCES {{schema}}.tags (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.logs (
+    id              uuid        NOT NULL,
+    user_profile_id uuid        NOT NULL,
+    project_id      uuid        NOT NULL,
+    content         text        NOT NULL,
+    like_count      bigint      DEFAULT 0,
+    comment_count   bigint      DEFAULT 0,
+    created_at      timestamptz NOT NULL,
+    updated_at      timestamptz NOT NULL,
+    deleted_at      timestamptz,
+    CONSTRAINT logs_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_{{schema}}_projects_logs FOREIGN KEY (project_id) REFERENCES {{schema}}.projects (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_logs_deleted_at ON {{schema}}.logs (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.media (
+    id             uuid         NOT NULL,
+    log_id         uuid         NOT NULL,
+    file_path      varchar(255) NOT NULL,
+    thumbnail_path varchar(255) NOT NULL,
+    type           varchar(20)  NOT NULL,
+    sort_order     bigint       DEFAULT 0,
+    CONSTRAINT media_pkey PRIMARY KEY (id),
+    CONSTRAINT chk_media_type CHECK (type IN ('image', 'video')),
+    CONSTRAINT fk_{{schema}}_logs_media FOREIGN KEY (log_id) REFERENCES {{schema}}.logs (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.comments (
+    id              uuid        NOT NULL,
+    user_profile_id uuid,
+    log_id          uuid,
+    body            text        NOT NULL,
+    created_at      timestamptz NOT NULL,
+    updated_at      timestamptz NOT NULL,
+    deleted_at      timestamptz,
+    CONSTRAINT comments_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_{{schema}}_logs_comments FOREIGN KEY (log_id) REFERENCES {{schema}}.logs (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_comments_deleted_at ON {{schema}}.comments (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.likes (
+    user_profile_id uuid,
+    log_id          uuid
+);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.sessions (
+    id           varchar(255) NOT NULL,
+    user_id      uuid         NOT NULL,
+    user_agent   varchar(500),
+    ip_address   varchar(64),
+    last_seen_at timestamptz  NOT NULL,
+    revoked_at   timestamptz,
+    created_at   timestamptz  NOT NULL,
+    updated_at   timestamptz  NOT NULL,
+    CONSTRAINT sessions_pkey PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_sessions_user_id ON {{schema}}.sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_sessions_revoked_at ON {{schema}}.sessions (revoked_at);
@@ -0,0 +1,4 @@
+ALTER TABLE {{schema}}.user_profiles DROP COLUMN IF EXISTS version;
+ALTER TABLE {{schema}}.comments DROP COLUMN IF EXISTS version;
+ALTER TABLE {{schema}}.logs DROP COLUMN IF EXISTS version;
+ALTER TABLE {{schema}}.projects DROP COLUMN IF EXISTS version;
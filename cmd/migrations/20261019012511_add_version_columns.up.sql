@@ -0,0 +1,7 @@
+-- Row versions for optimistic concurrency control: every update bumps the version and clients
+-- send it back in If-Match, so concurrent edits fail instead of overwriting each other.
+
+ALTER TABLE {{schema}}.projects ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE {{schema}}.logs ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE {{schema}}.comments ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE {{schema}}.user_profiles ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- Dropping the columns drops their indexes too.
+ALTER TABLE {{schema}}.user_profiles DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE {{schema}}.tags DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE {{schema}}.comments DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE {{schema}}.logs DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE {{schema}}.projects DROP COLUMN IF EXISTS search_vector;
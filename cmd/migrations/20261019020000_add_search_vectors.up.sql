@@ -0,0 +1,29 @@
+-- Full-text search: each searchable table gets a generated tsvector of its text columns, weighted
+-- so that titles and names rank above bodies, and a GIN index to match it against queries. The
+-- 'simple' configuration does not stem, so it works the same for every language users write in.
+
+ALTER TABLE {{schema}}.projects ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_projects_search_vector ON {{schema}}.projects USING GIN (search_vector);
+
+ALTER TABLE {{schema}}.logs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(content, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_logs_search_vector ON {{schema}}.logs USING GIN (search_vector);
+
+ALTER TABLE {{schema}}.comments ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(body, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_comments_search_vector ON {{schema}}.comments USING GIN (search_vector);
+
+ALTER TABLE {{schema}}.tags ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (setweight(to_tsvector('simple', coalesce(name, '')), 'A')) STORED;
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_tags_search_vector ON {{schema}}.tags USING GIN (search_vector);
+
+ALTER TABLE {{schema}}.user_profiles ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(first_name, '') || ' ' || coalesce(last_name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(bio, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_{{schema}}_user_profiles_search_vector ON {{schema}}.user_profiles USING GIN (search_vector);
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/revandpratama/lognest/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SchemaDrift describes one difference between the live database and the entity models.
+type SchemaDrift struct {
+	Table  string
+	Kind   string
+	Detail string
+}
+
+type liveColumn struct {
+	TableName              string
+	ColumnName             string
+	UdtName                string
+	CharacterMaximumLength *int
+	IsNullable             string
+}
+
+type liveForeignKey struct {
+	TableName        string
+	ColumnName       string
+	ReferencedTable  string
+	ReferencedColumn string
+}
+
+// ignoredTables are owned by lognest tooling rather than by an entity.
+var ignoredTables = map[string]bool{
+	"schema_migrations": true,
+}
+
+// CheckSchemaDrift compares tables, columns, indexes and foreign keys of the live schema
+// with what the entity models declare, and reports pending migrations.
+func CheckSchemaDrift(db *gorm.DB) ([]SchemaDrift, error) {
+	var drifts []SchemaDrift
+
+	states, err := MigrationStatus(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			drifts = append(drifts, SchemaDrift{Table: "-", Kind: "pending_migration", Detail: fmt.Sprintf("%d_%s", state.Version, state.Name)})
+		} else if state.Modified {
+			drifts = append(drifts, SchemaDrift{Table: "-", Kind: "modified_migration", Detail: fmt.Sprintf("%d_%s", state.Version, state.Name)})
+		}
+	}
+
+	expected, err := expectedSchemas(db)
+	if err != nil {
+		return nil, err
+	}
+
+	liveColumns, err := loadLiveColumns(db)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedFKs := map[string]bool{}
+	for _, s := range expected {
+		table := bareTableName(s.Table)
+
+		columns, ok := liveColumns[table]
+		if !ok {
+			drifts = append(drifts, SchemaDrift{Table: table, Kind: "missing_table", Detail: "table does not exist"})
+			continue
+		}
+
+		drifts = append(drifts, compareColumns(db, table, s, columns)...)
+
+		if !isJoinTable(s) {
+			indexDrifts, err := compareIndexes(db, table, s)
+			if err != nil {
+				return nil, err
+			}
+			drifts = append(drifts, indexDrifts...)
+		}
+
+		for _, rel := range s.Relationships.Relations {
+			constraint := rel.ParseConstraint()
+			if constraint == nil || constraint.Schema == nil || constraint.ReferenceSchema == nil {
+				continue
+			}
+			for i, fk := range constraint.ForeignKeys {
+				expectedFKs[foreignKeyKey(
+					bareTableName(constraint.Schema.Table), fk.DBName,
+					bareTableName(constraint.ReferenceSchema.Table), constraint.References[i].DBName,
+				)] = true
+			}
+		}
+	}
+
+	for table := range liveColumns {
+		if ignoredTables[table] {
+			continue
+		}
+		if _, ok := expected[table]; !ok {
+			drifts = append(drifts, SchemaDrift{Table: table, Kind: "extra_table", Detail: "table has no entity model"})
+		}
+	}
+
+	liveFKs, err := loadLiveForeignKeys(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range expectedFKs {
+		if !liveFKs[key] {
+			drifts = append(drifts, SchemaDrift{Table: strings.SplitN(key, ".", 2)[0], Kind: "missing_foreign_key", Detail: key})
+		}
+	}
+	for key := range liveFKs {
+		if !expectedFKs[key] {
+			drifts = append(drifts, SchemaDrift{Table: strings.SplitN(key, ".", 2)[0], Kind: "extra_foreign_key", Detail: key})
+		}
+	}
+
+	sort.SliceStable(drifts, func(i, j int) bool {
+		if drifts[i].Table != drifts[j].Table {
+			return drifts[i].Table < drifts[j].Table
+		}
+		return drifts[i].Kind < drifts[j].Kind
+	})
+
+	return drifts, nil
+}
+
+// expectedSchemas parses every model plus the many2many join tables they declare, keyed by bare table name.
+func expectedSchemas(db *gorm.DB) (map[string]*schema.Schema, error) {
+	schemas := map[string]*schema.Schema{}
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+
+		schemas[bareTableName(stmt.Schema.Table)] = stmt.Schema
+
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if rel.JoinTable != nil {
+				schemas[bareTableName(rel.JoinTable.Table)] = rel.JoinTable
+			}
+		}
+	}
+
+	return schemas, nil
+}
+
+func compareColumns(db *gorm.DB, table string, s *schema.Schema, live map[string]liveColumn) []SchemaDrift {
+	var drifts []SchemaDrift
+
+	for _, dbName := range s.DBNames {
+		field := s.FieldsByDBName[dbName]
+
+		column, ok := live[dbName]
+		if !ok {
+			drifts = append(drifts, SchemaDrift{Table: table, Kind: "missing_column", Detail: dbName})
+			continue
+		}
+
+		expectedType := normalizeType(db.Migrator().FullDataTypeOf(field).SQL)
+		liveType := normalizeLiveType(column)
+		if expectedType != liveType {
+			drifts = append(drifts, SchemaDrift{Table: table, Kind: "type_mismatch", Detail: fmt.Sprintf("%s: model %s, database %s", dbName, expectedType, liveType)})
+		}
+
+		expectedNotNull := field.NotNull || field.PrimaryKey
+		liveNotNull := column.IsNullable == "NO"
+		if expectedNotNull != liveNotNull {
+			drifts = append(drifts, SchemaDrift{Table: table, Kind: "nullability_mismatch", Detail: fmt.Sprintf("%s: model not null=%t, database not null=%t", dbName, expectedNotNull, liveNotNull)})
+		}
+	}
+
+	for name := range live {
+		if _, ok := s.FieldsByDBName[name]; !ok {
+			drifts = append(drifts, SchemaDrift{Table: table, Kind: "extra_column", Detail: name})
+		}
+	}
+
+	return drifts
+}
+
+func compareIndexes(db *gorm.DB, table string, s *schema.Schema) ([]SchemaDrift, error) {
+	var drifts []SchemaDrift
+
+	liveIndexes, err := db.Migrator().GetIndexes(s.Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load indexes of %s: %w", table, err)
+	}
+
+	for _, index := range s.ParseIndexes() {
+		var columns []string
+		for _, field := range index.Fields {
+			if field.Field != nil {
+				columns = append(columns, field.DBName)
+			}
+		}
+		unique := index.Class == "UNIQUE"
+
+		found := false
+		for _, live := range liveIndexes {
+			liveUnique, _ := live.Unique()
+			if strings.Join(live.Columns(), ",") == strings.Join(columns, ",") && liveUnique == unique {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			kind := "index"
+			if unique {
+				kind = "unique index"
+			}
+			drifts = append(drifts, SchemaDrift{Table: table, Kind: "missing_index", Detail: fmt.Sprintf("%s on (%s)", kind, strings.Join(columns, ", "))})
+		}
+	}
+
+	return drifts, nil
+}
+
+func loadLiveColumns(db *gorm.DB) (map[string]map[string]liveColumn, error) {
+	var rows []liveColumn
+	if err := db.Raw(`
+		SELECT table_name, column_name, udt_name, character_maximum_length, is_nullable
+		FROM information_schema.columns
+		WHERE table_schema = ?`, config.ENV.LOGNEST_SCHEMA).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	tables := map[string]map[string]liveColumn{}
+	for _, row := range rows {
+		if tables[row.TableName] == nil {
+			tables[row.TableName] = map[string]liveColumn{}
+		}
+		tables[row.TableName][row.ColumnName] = row
+	}
+
+	return tables, nil
+}
+
+func loadLiveForeignKeys(db *gorm.DB) (map[string]bool, error) {
+	var rows []liveForeignKey
+	if err := db.Raw(`
+		SELECT kcu.table_name, kcu.column_name,
+		       ccu.table_name AS referenced_table, ccu.column_name AS referenced_column
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+		  ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema
+		JOIN information_schema.constraint_column_usage ccu
+		  ON ccu.constraint_name = tc.constraint_name AND ccu.constraint_schema = tc.table_schema
+		WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = ?`, config.ENV.LOGNEST_SCHEMA).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for _, row := range rows {
+		keys[foreignKeyKey(row.TableName, row.ColumnName, row.ReferencedTable, row.ReferencedColumn)] = true
+	}
+
+	return keys, nil
+}
+
+func foreignKeyKey(table string, column string, referencedTable string, referencedColumn string) string {
+	return fmt.Sprintf("%s.%s -> %s.%s", table, column, referencedTable, referencedColumn)
+}
+
+func isJoinTable(s *schema.Schema) bool {
+	return s.ModelType == nil || s.ModelType.Name() == ""
+}
+
+func bareTableName(table string) string {
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		return table[i+1:]
+	}
+	return table
+}
+
+// normalizeType reduces a GORM column definition to its bare type, e.g. "varchar(255) NOT NULL" -> "varchar(255)".
+func normalizeType(definition string) string {
+	definition = strings.ToLower(strings.TrimSpace(definition))
+	for _, keyword := range []string{" not null", " default", " unique", " primary key", " check", " comment"} {
+		if i := strings.Index(definition, keyword); i >= 0 {
+			definition = definition[:i]
+		}
+	}
+
+	switch definition {
+	case "int", "integer", "serial":
+		return "integer"
+	case "bigserial":
+		return "bigint"
+	case "bool":
+		return "boolean"
+	case "timestamp with time zone":
+		return "timestamptz"
+	}
+	return definition
+}
+
+func normalizeLiveType(column liveColumn) string {
+	switch column.UdtName {
+	case "int2":
+		return "smallint"
+	case "int4":
+		return "integer"
+	case "int8":
+		return "bigint"
+	case "bool":
+		return "boolean"
+	case "float4":
+		return "real"
+	case "float8":
+		return "double precision"
+	case "varchar", "bpchar":
+		name := "varchar"
+		if column.UdtName == "bpchar" {
+			name = "char"
+		}
+		if column.CharacterMaximumLength != nil {
+			return fmt.Sprintf("%s(%d)", name, *column.CharacterMaximumLength)
+		}
+		return name
+	}
+	return column.UdtName
+}
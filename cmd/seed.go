@@ -0,0 +1,479 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/config"
+	interactionEntity "github.com/revandpratama/lognest/internal/modules/interaction/entity"
+	logEntity "github.com/revandpratama/lognest/internal/modules/log/entity"
+	projectEntity "github.com/revandpratama/lognest/internal/modules/project/entity"
+	tagEntity "github.com/revandpratama/lognest/internal/modules/tag/entity"
+	userProfileEntity "github.com/revandpratama/lognest/internal/modules/user-profile/entity"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const seedBatchSize = 500
+
+// SeedSize controls how much fixture data SeedDatabase generates.
+type SeedSize struct {
+	Users              int
+	Tags               int
+	ProjectsPerUser    int
+	LogsPerProject     int
+	MaxMediaPerLog     int
+	MaxCommentsPerLog  int
+	MaxLikesPerLog     int
+	MaxFollowsPerUser  int
+	MaxTagsPerProject  int
+	HistoryWindowInDay int
+}
+
+var SeedSizes = map[string]SeedSize{
+	"small": {
+		Users: 10, Tags: 15, ProjectsPerUser: 3, LogsPerProject: 5,
+		MaxMediaPerLog: 2, MaxCommentsPerLog: 3, MaxLikesPerLog: 5, MaxFollowsPerUser: 5, MaxTagsPerProject: 3,
+		HistoryWindowInDay: 30,
+	},
+	"medium": {
+		Users: 100, Tags: 50, ProjectsPerUser: 5, LogsPerProject: 20,
+		MaxMediaPerLog: 3, MaxCommentsPerLog: 5, MaxLikesPerLog: 20, MaxFollowsPerUser: 20, MaxTagsPerProject: 4,
+		HistoryWindowInDay: 180,
+	},
+	"large": {
+		Users: 1000, Tags: 200, ProjectsPerUser: 10, LogsPerProject: 50,
+		MaxMediaPerLog: 3, MaxCommentsPerLog: 10, MaxLikesPerLog: 50, MaxFollowsPerUser: 50, MaxTagsPerProject: 5,
+		HistoryWindowInDay: 730,
+	},
+}
+
+type SeedOptions struct {
+	Size string
+	Seed int64
+	// Reset empties the seeded tables first, so the same seed can be loaded again.
+	Reset bool
+}
+
+// userFollower mirrors the user_followers join table used by UserProfile.Following/Followers.
+type userFollower struct {
+	FollowerID  uuid.UUID
+	FollowingID uuid.UUID
+}
+
+func (userFollower) TableName() string {
+	return fmt.Sprintf("%s.%s", config.ENV.LOGNEST_SCHEMA, "user_followers")
+}
+
+// seeder produces deterministic fixture data: the same seed always yields the same rows.
+type seeder struct {
+	db   *gorm.DB
+	rng  *rand.Rand
+	size SeedSize
+	now  time.Time
+}
+
+// SeedDatabase fills the database with pseudo-random but reproducible fixture data.
+func SeedDatabase(db *gorm.DB, opts SeedOptions) error {
+	if config.ENV.APP_ENV == "production" {
+		return errors.New("refusing to seed a production database")
+	}
+
+	size, ok := SeedSizes[opts.Size]
+	if !ok {
+		return fmt.Errorf("unknown seed size %q, expected small, medium or large", opts.Size)
+	}
+
+	s := &seeder{
+		db:   db,
+		rng:  rand.New(rand.NewSource(opts.Seed)),
+		size: size,
+		// A fixed reference time keeps generated timestamps reproducible between runs.
+		now: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	started := time.Now()
+
+	if opts.Reset {
+		if err := resetSeedTables(db); err != nil {
+			return fmt.Errorf("failed to reset seeded tables: %w", err)
+		}
+	}
+
+	users, err := s.seedUsers(opts.Seed)
+	if err != nil {
+		return fmt.Errorf("failed to seed user profiles: %w", err)
+	}
+
+	tags, err := s.seedTags()
+	if err != nil {
+		return fmt.Errorf("failed to seed tags: %w", err)
+	}
+
+	stats, err := s.seedContent(users, tags)
+	if err != nil {
+		return fmt.Errorf("failed to seed projects and logs: %w", err)
+	}
+
+	log.Info().
+		Int("users", len(users)).
+		Int("tags", len(tags)).
+		Int("projects", stats.projects).
+		Int("logs", stats.logs).
+		Int("media", stats.media).
+		Int("comments", stats.comments).
+		Int("likes", stats.likes).
+		Dur("took", time.Since(started)).
+		Msgf("seeded %s dataset with seed %d", opts.Size, opts.Seed)
+
+	return nil
+}
+
+// resetSeedTables truncates every table the seeder writes to, and any table referencing them.
+func resetSeedTables(db *gorm.DB) error {
+	models := []any{
+		&userProfileEntity.UserProfile{},
+		&userFollower{},
+		&tagEntity.Tag{},
+		&projectEntity.Project{},
+		&projectEntity.ProjectTag{},
+		&logEntity.Log{},
+		&logEntity.Media{},
+		&interactionEntity.Comment{},
+		&interactionEntity.Like{},
+	}
+
+	tables := make([]string, 0, len(models))
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		tables = append(tables, stmt.Schema.Table)
+	}
+
+	if err := db.Exec(fmt.Sprintf("TRUNCATE TABLE %s CASCADE", strings.Join(tables, ", "))).Error; err != nil {
+		return err
+	}
+
+	log.Info().Strs("tables", tables).Msg("truncated seeded tables")
+	return nil
+}
+
+func (s *seeder) seedUsers(seed int64) ([]userProfileEntity.UserProfile, error) {
+	users := make([]userProfileEntity.UserProfile, s.size.Users)
+	for i := range users {
+		firstName := seedFirstNames[s.rng.Intn(len(seedFirstNames))]
+		lastName := seedLastNames[s.rng.Intn(len(seedLastNames))]
+		createdAt := s.pastTime()
+
+		users[i] = userProfileEntity.UserProfile{
+			UserID:     s.uuid(),
+			Email:      fmt.Sprintf("%s.%s.%d.s%d@seed.lognest.dev", strings.ToLower(firstName), strings.ToLower(lastName), i, seed),
+			FirstName:  firstName,
+			LastName:   lastName,
+			Bio:        s.sentence(8, 20),
+			AvatarPath: fmt.Sprintf("avatars/seed-%d.png", s.rng.Intn(50)),
+			CreatedAt:  createdAt,
+			UpdatedAt:  createdAt,
+		}
+	}
+
+	var follows []userFollower
+	if len(users) > 1 {
+		for i := range users {
+			targets := s.pick(len(users), s.rng.Intn(s.size.MaxFollowsPerUser+1))
+			for _, j := range targets {
+				if i == j {
+					continue
+				}
+				follows = append(follows, userFollower{FollowerID: users[i].UserID, FollowingID: users[j].UserID})
+				users[i].FollowingCount++
+				users[j].FollowerCount++
+			}
+		}
+	}
+
+	if err := s.db.Omit(clause.Associations).CreateInBatches(users, seedBatchSize).Error; err != nil {
+		return nil, err
+	}
+
+	if len(follows) > 0 {
+		if err := s.db.CreateInBatches(follows, seedBatchSize).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return users, nil
+}
+
+func (s *seeder) seedTags() ([]tagEntity.Tag, error) {
+	tags := make([]tagEntity.Tag, s.size.Tags)
+	for i := range tags {
+		name := seedTagNames[i%len(seedTagNames)]
+		if i >= len(seedTagNames) {
+			name = fmt.Sprintf("%s-%d", name, i/len(seedTagNames))
+		}
+
+		createdAt := s.pastTime()
+		tags[i] = tagEntity.Tag{
+			ID:        s.uuid(),
+			Name:      name,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+	}
+
+	if err := s.db.CreateInBatches(tags, seedBatchSize).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+type seedStats struct {
+	projects, logs, media, comments, likes int
+}
+
+// seedContent inserts projects, logs, media, comments and likes one user at a time
+// so the large preset does not need to hold the whole dataset in memory.
+func (s *seeder) seedContent(users []userProfileEntity.UserProfile, tags []tagEntity.Tag) (seedStats, error) {
+	var stats seedStats
+
+	for _, user := range users {
+		var (
+			projects    []projectEntity.Project
+			projectTags []projectEntity.ProjectTag
+			logs        []logEntity.Log
+			media       []logEntity.Media
+			comments    []interactionEntity.Comment
+			likes       []interactionEntity.Like
+		)
+
+		for p := 0; p < s.size.ProjectsPerUser; p++ {
+			title := s.title()
+			isPublic := s.rng.Intn(4) != 0
+			createdAt := s.pastTime()
+
+			project := projectEntity.Project{
+				ID:             s.uuid(),
+				UserProfileID:  user.UserID,
+				Title:          title,
+				Description:    s.sentence(15, 40),
+				Slug:           fmt.Sprintf("%s-%s", seedSlug(title), s.uuid().String()[:6]),
+				CoverImagePath: fmt.Sprintf("covers/seed-%d.jpg", s.rng.Intn(50)),
+				IsPublic:       &isPublic,
+				CreatedAt:      createdAt,
+				UpdatedAt:      createdAt,
+			}
+			projects = append(projects, project)
+
+			for _, t := range s.pick(len(tags), 1+s.rng.Intn(s.size.MaxTagsPerProject)) {
+				projectTags = append(projectTags, projectEntity.ProjectTag{ProjectID: project.ID, TagID: tags[t].ID})
+			}
+
+			for l := 0; l < s.size.LogsPerProject; l++ {
+				logCreatedAt := s.timeAfter(createdAt)
+				entry := logEntity.Log{
+					ID:            s.uuid(),
+					UserProfileID: user.UserID,
+					ProjectID:     project.ID,
+					Content:       s.paragraph(),
+					CreatedAt:     logCreatedAt,
+					UpdatedAt:     logCreatedAt,
+				}
+
+				mediaCount := s.rng.Intn(s.size.MaxMediaPerLog + 1)
+				for m := 0; m < mediaCount; m++ {
+					mediaType := "image"
+					if s.rng.Intn(5) == 0 {
+						mediaType = "video"
+					}
+					media = append(media, logEntity.Media{
+						ID:            s.uuid(),
+						LogID:         entry.ID,
+						FilePath:      fmt.Sprintf("logs/seed-%d.%s", s.rng.Intn(500), seedExtension(mediaType)),
+						ThumbnailPath: fmt.Sprintf("thumbnails/seed-%d.jpg", s.rng.Intn(500)),
+						Type:          mediaType,
+						SortOrder:     m,
+					})
+				}
+
+				commentCount := s.rng.Intn(s.size.MaxCommentsPerLog + 1)
+				for c := 0; c < commentCount; c++ {
+					commentedAt := s.timeAfter(logCreatedAt)
+					comments = append(comments, interactionEntity.Comment{
+						ID:            s.uuid(),
+						UserProfileID: users[s.rng.Intn(len(users))].UserID,
+						LogID:         entry.ID,
+						Body:          s.sentence(3, 25),
+						CreatedAt:     commentedAt,
+						UpdatedAt:     commentedAt,
+					})
+					entry.CommentCount++
+				}
+
+				for _, u := range s.pick(len(users), s.rng.Intn(s.size.MaxLikesPerLog+1)) {
+					likes = append(likes, interactionEntity.Like{UserProfileID: users[u].UserID, LogID: entry.ID})
+					entry.LikeCount++
+				}
+
+				logs = append(logs, entry)
+			}
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Omit(clause.Associations).CreateInBatches(projects, seedBatchSize).Error; err != nil {
+				return err
+			}
+			if err := tx.CreateInBatches(projectTags, seedBatchSize).Error; err != nil {
+				return err
+			}
+			if err := tx.Omit(clause.Associations).CreateInBatches(logs, seedBatchSize).Error; err != nil {
+				return err
+			}
+			if len(media) > 0 {
+				if err := tx.CreateInBatches(media, seedBatchSize).Error; err != nil {
+					return err
+				}
+			}
+			if len(comments) > 0 {
+				if err := tx.CreateInBatches(comments, seedBatchSize).Error; err != nil {
+					return err
+				}
+			}
+			if len(likes) > 0 {
+				if err := tx.CreateInBatches(likes, seedBatchSize).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+
+		stats.projects += len(projects)
+		stats.logs += len(logs)
+		stats.media += len(media)
+		stats.comments += len(comments)
+		stats.likes += len(likes)
+	}
+
+	return stats, nil
+}
+
+// uuid draws a v4 UUID from the seeded source so IDs are reproducible.
+func (s *seeder) uuid() uuid.UUID {
+	id, err := uuid.NewRandomFromReader(s.rng)
+	if err != nil {
+		// math/rand never fails to read.
+		panic(err)
+	}
+	return id
+}
+
+// pick returns up to n distinct indexes in [0, total).
+func (s *seeder) pick(total int, n int) []int {
+	if n > total {
+		n = total
+	}
+
+	// Rejection sampling avoids allocating a full permutation for every small pick.
+	if n*4 < total {
+		seen := make(map[int]struct{}, n)
+		picked := make([]int, 0, n)
+		for len(picked) < n {
+			i := s.rng.Intn(total)
+			if _, ok := seen[i]; ok {
+				continue
+			}
+			seen[i] = struct{}{}
+			picked = append(picked, i)
+		}
+		return picked
+	}
+
+	return s.rng.Perm(total)[:n]
+}
+
+func (s *seeder) pastTime() time.Time {
+	window := time.Duration(s.size.HistoryWindowInDay) * 24 * time.Hour
+	return s.now.Add(-time.Duration(s.rng.Int63n(int64(window))))
+}
+
+func (s *seeder) timeAfter(t time.Time) time.Time {
+	remaining := s.now.Sub(t)
+	if remaining <= 0 {
+		return t
+	}
+	return t.Add(time.Duration(s.rng.Int63n(int64(remaining))))
+}
+
+func (s *seeder) title() string {
+	words := make([]string, 2+s.rng.Intn(4))
+	for i := range words {
+		words[i] = seedWords[s.rng.Intn(len(seedWords))]
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ")
+}
+
+func (s *seeder) sentence(minWords int, maxWords int) string {
+	words := make([]string, minWords+s.rng.Intn(maxWords-minWords+1))
+	for i := range words {
+		words[i] = seedWords[s.rng.Intn(len(seedWords))]
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ") + "."
+}
+
+func (s *seeder) paragraph() string {
+	sentences := make([]string, 2+s.rng.Intn(5))
+	for i := range sentences {
+		sentences[i] = s.sentence(5, 18)
+	}
+	return strings.Join(sentences, " ")
+}
+
+func seedSlug(title string) string {
+	slug := strings.ToLower(title)
+	if len(slug) > 18 {
+		slug = slug[:18]
+	}
+	return strings.Trim(strings.ReplaceAll(slug, " ", "-"), "-")
+}
+
+func seedExtension(mediaType string) string {
+	if mediaType == "video" {
+		return "mp4"
+	}
+	return "jpg"
+}
+
+var seedFirstNames = []string{
+	"Ayu", "Budi", "Citra", "Dimas", "Eka", "Fajar", "Gita", "Hana", "Indra", "Joko",
+	"Kirana", "Lukas", "Maya", "Nadia", "Oscar", "Putri", "Rizky", "Sari", "Tono", "Wulan",
+}
+
+var seedLastNames = []string{
+	"Pratama", "Santoso", "Wijaya", "Saputra", "Lestari", "Hidayat", "Kusuma", "Nugroho", "Permata", "Setiawan",
+}
+
+var seedTagNames = []string{
+	"go", "rust", "typescript", "react", "postgres", "docker", "kubernetes", "azure", "design", "devlog",
+	"gamedev", "ml", "cli", "api", "frontend", "backend", "testing", "performance", "security", "oss",
+}
+
+var seedWords = []string{
+	"build", "ship", "refactor", "debug", "deploy", "sprint", "feature", "release", "query", "index",
+	"cache", "latency", "schema", "module", "handler", "router", "token", "session", "upload", "thumbnail",
+	"today", "finally", "progress", "milestone", "bug", "fix", "idea", "prototype", "design", "review",
+	"the", "a", "with", "after", "before", "during", "while", "new", "old", "fast",
+	"slow", "clean", "messy", "small", "large", "simple", "tricky", "weekend", "morning", "night",
+}
@@ -23,6 +23,12 @@ type Config struct {
 	JWT_SECRET            string `mapstructure:"JWT_SECRET"`
 	JWT_EXPIRATION_SECOND string `mapstructure:"JWT_EXPIRATION_SECOND"`
 
+	// HS256 (shared JWT_SECRET), RS256 or EdDSA (public keys from JWT_JWKS_URL or JWT_JWKS_FILE)
+	JWT_ALGORITHM                    string `mapstructure:"JWT_ALGORITHM"`
+	JWT_JWKS_URL                     string `mapstructure:"JWT_JWKS_URL"`
+	JWT_JWKS_FILE                    string `mapstructure:"JWT_JWKS_FILE"`
+	JWT_JWKS_REFRESH_INTERVAL_SECOND string `mapstructure:"JWT_JWKS_REFRESH_INTERVAL_SECOND"`
+
 	DB_HOST     string `mapstructure:"DB_HOST"`
 	DB_PORT     string `mapstructure:"DB_PORT"`
 	DB_USER     string `mapstructure:"DB_USER"`
@@ -35,6 +41,26 @@ type Config struct {
 	AZURE_STORAGE_CONNECTION_STRING              string `mapstructure:"AZURE_STORAGE_CONNECTION_STRING"`
 	AZURE_STORAGE_CONTAINER_NAME                 string `mapstructure:"AZURE_STORAGE_CONTAINER_NAME"`
 	AZURE_STORAGE_URL_EXPIRY_DURATION_IN_MINUTES string `mapstructure:"AZURE_STORAGE_URL_EXPIRY_DURATION_IN_MINUTES"`
+
+	// Whether PUT, PATCH and DELETE on versioned resources must carry an If-Match header
+	REQUIRE_IF_MATCH string `mapstructure:"REQUIRE_IF_MATCH"`
+
+	// Bearer token required to scrape /metrics; when empty /metrics is served without authentication
+	// outside production and not at all in production
+	METRICS_TOKEN string `mapstructure:"METRICS_TOKEN"`
+
+	// none, stdout or otlp; the OTLP exporter sends to TRACING_OTLP_ENDPOINT over HTTP
+	TRACING_EXPORTER      string `mapstructure:"TRACING_EXPORTER"`
+	TRACING_OTLP_ENDPOINT string `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	// Fraction of new traces recorded, between 0 and 1
+	TRACING_SAMPLE_RATIO string `mapstructure:"TRACING_SAMPLE_RATIO"`
+
+	// trace, debug, info, warn or error; json or console
+	LOG_LEVEL  string `mapstructure:"LOG_LEVEL"`
+	LOG_FORMAT string `mapstructure:"LOG_FORMAT"`
+
+	// Seconds /readyz reports not ready before the server stops accepting connections on shutdown
+	SHUTDOWN_DRAIN_SECOND string `mapstructure:"SHUTDOWN_DRAIN_SECOND"`
 }
 
 var ENV Config
@@ -47,6 +73,14 @@ func LoadConfig() error {
 	viper.AddConfigPath(".")
 
 	viper.SetDefault("REST_PORT", "8080")
+	viper.SetDefault("JWT_ALGORITHM", "HS256")
+	viper.SetDefault("JWT_JWKS_REFRESH_INTERVAL_SECOND", "3600")
+	viper.SetDefault("REQUIRE_IF_MATCH", "true")
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", "1")
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("SHUTDOWN_DRAIN_SECOND", "0")
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
@@ -2,10 +2,14 @@ package app
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/health"
+	"github.com/revandpratama/lognest/pkg/token"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
@@ -14,6 +18,10 @@ type App struct {
 	fiberApp     *fiber.App
 	DB           *gorm.DB
 	AzblobClient *azblob.Client
+	tokenKeySet  *token.KeySet
+
+	health          *health.Checker
+	shutdownTracing func(context.Context) error
 }
 
 type Option func(*App) error
@@ -29,6 +37,17 @@ func NewApp(opts ...Option) (*App, error) {
 }
 
 func (a *App) Stop() error {
+	if a.health != nil {
+		a.health.SetReady(false)
+
+		// Keep serving while load balancers see /readyz fail and stop routing here.
+		drain, err := strconv.Atoi(config.ENV.SHUTDOWN_DRAIN_SECOND)
+		if err == nil && drain > 0 {
+			log.Info().Msgf("draining for %ds before shutting down", drain)
+			time.Sleep(time.Duration(drain) * time.Second)
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -38,6 +57,17 @@ func (a *App) Stop() error {
 		}
 	}
 
+	// Flushed after the server so the spans of in-flight requests are exported.
+	if a.shutdownTracing != nil {
+		if err := a.shutdownTracing(ctx); err != nil {
+			log.Error().Err(err).Msg("failed to flush traces")
+		}
+	}
+
+	if a.tokenKeySet != nil {
+		a.tokenKeySet.Close()
+	}
+
 	if a.DB != nil {
 		sqlDb, _ := a.DB.DB()
 		if err := sqlDb.Close(); err != nil {
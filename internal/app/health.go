@@ -0,0 +1,29 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/health"
+)
+
+// newHealthChecker checks the dependencies the app was built with. Auth4me is probed with a plain
+// client so probes do not show up in its request metrics and traces.
+func newHealthChecker(app *App) *health.Checker {
+	var checks []health.Check
+
+	if app.DB != nil {
+		checks = append(checks, health.DBCheck(app.DB))
+	}
+
+	if app.AzblobClient != nil {
+		checks = append(checks, health.BlobContainerCheck(app.AzblobClient, config.ENV.AZURE_STORAGE_CONTAINER_NAME))
+	}
+
+	if config.ENV.AUTH4ME_URL != "" {
+		checks = append(checks, health.HTTPCheck("auth4me", &http.Client{}, config.ENV.AUTH4ME_URL))
+	}
+
+	return health.NewChecker(2*time.Second, time.Second, checks...)
+}
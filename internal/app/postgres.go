@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/metrics"
+	"github.com/revandpratama/lognest/pkg/tracing"
 	"github.com/rs/zerolog/log"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -43,6 +45,14 @@ func WithDB() Option {
 			return fmt.Errorf("database ping failed: %w", err)
 		}
 
+		if err := metrics.RegisterDB(db, config.ENV.DB_NAME); err != nil {
+			return fmt.Errorf("failed to register database metrics: %w", err)
+		}
+
+		if err := tracing.RegisterDB(db); err != nil {
+			return fmt.Errorf("failed to register database tracing: %w", err)
+		}
+
 		app.DB = db
 
 		log.Info().Msg("database connected")
@@ -10,9 +10,13 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/encryptcookie"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/internal/middlewares"
 	route "github.com/revandpratama/lognest/internal/routes"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/health"
+	"github.com/revandpratama/lognest/pkg/metrics"
+	"github.com/revandpratama/lognest/pkg/tracing"
 
 	// "github.com/revandpratama/lognest/internal/routes"
 	"github.com/rs/zerolog/log"
@@ -23,13 +27,35 @@ func WithRESTServer() Option {
 
 		fiberApp := fiber.New(fiber.Config{
 			DisableStartupMessage: true,
+			ErrorHandler:          errorhandler.Handler,
 		})
 
+		fiberApp.Use(middlewares.TracingMiddleware())
+
+		fiberApp.Use(middlewares.RequestIDMiddleware())
+
+		fiberApp.Use(middlewares.RequestLoggerMiddleware())
+
+		fiberApp.Use(middlewares.MetricsMiddleware())
+
+		fiberApp.Use(middlewares.RecoverMiddleware())
+
 		fiberApp.Use(func(c *fiber.Ctx) error {
 			c.Set("Content-Type", "application/json")
 			return c.Next()
 		})
 
+		// * Probes are registered ahead of the rate limiter so they are never throttled; /readyz
+		// * reuses its report for a second, so probing it often does not load the dependencies
+		app.health = newHealthChecker(app)
+		fiberApp.Hooks().OnListen(func(fiber.ListenData) error {
+			app.health.SetReady(true)
+			return nil
+		})
+
+		fiberApp.Get("/healthz", health.Liveness)
+		fiberApp.Get("/readyz", app.health.Readiness)
+
 		fiberApp.Use(limiter.New(limiter.Config{
 			Max:        50,
 			Expiration: 5 * time.Second,
@@ -37,41 +63,45 @@ func WithRESTServer() Option {
 				return c.IP()
 			},
 			LimitReached: func(c *fiber.Ctx) error {
-				return c.Status(429).SendString("Too Many Requests")
+				return errorhandler.BuildError(c, fiber.ErrTooManyRequests, nil)
 			},
 		}))
 
 		fiberApp.Use(cors.New(cors.Config{
 			AllowOrigins:     config.ENV.CORS_ALLOWED_ORIGINS,
 			// AllowCredentials: true,
-			AllowHeaders:     "Origin, Content-Type, Accept, Content-Length, Authorization, Accept-Encoding, X-CSRF-Token, X-Requested-With, X-Refresh-Token",
+			AllowHeaders:     "Origin, Content-Type, Accept, Content-Length, Authorization, Accept-Encoding, X-CSRF-Token, X-Requested-With, X-Refresh-Token, If-Match, If-None-Match, If-Modified-Since, X-Request-ID",
+			ExposeHeaders:    "ETag, Last-Modified, X-Request-ID",
 		}))
 
 		fiberApp.Use(encryptcookie.New(encryptcookie.Config{
 			Key: config.ENV.COOKIE_SECRET,
 		}))
 
-		fiberApp.Use(logger.New(logger.Config{
-			Format:        "${time} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${error}\n",
-			TimeFormat:    "15:04:05",
-			TimeZone:      "Local",
-			TimeInterval:  500 * time.Millisecond,
-			Output:        os.Stdout,
-			DisableColors: false,
-		}))
+		fiberApp.Use(middlewares.CSRFMiddleware())
 
 		fiberApp.Get("/hello", func(c *fiber.Ctx) error {
 			return c.SendString("Hello, World!")
 		})
 
+		// * Without a token /metrics is public, so production only serves it with one
+		if config.ENV.METRICS_TOKEN != "" || config.ENV.APP_ENV != "production" {
+			fiberApp.Get("/metrics", metrics.Handler())
+		} else {
+			log.Warn().Msg("METRICS_TOKEN is not set, /metrics is disabled")
+		}
+
 		api := fiberApp.Group("/api")
 
-		api.Get("/test-700ms", func(c *fiber.Ctx) error {
-			time.Sleep(500 * time.Millisecond)
-			return c.SendString("Hello. 700ms delay!")
-		})
+		if config.ENV.APP_ENV != "production" {
+			api.Get("/test-700ms", func(c *fiber.Ctx) error {
+				time.Sleep(500 * time.Millisecond)
+				return c.SendString("Hello. 700ms delay!")
+			})
+		}
 
-		httpClient := &http.Client{}
+		// * Only used for Auth4me
+		httpClient := &http.Client{Transport: metrics.InstrumentAuth4me(tracing.InstrumentAuth4me(nil))}
 
 		// * Initialize routes
 		route.InitRoutes(api, app.DB, httpClient, app.AzblobClient)
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/token"
+	"github.com/rs/zerolog/log"
+)
+
+func WithTokenVerifier() Option {
+	return func(app *App) error {
+
+		algorithm := token.Algorithm()
+		if algorithm == token.AlgorithmHS256 {
+			log.Info().Msg("token verifier using HS256 shared secret")
+			return nil
+		}
+
+		keySet, err := token.NewKeySet(config.ENV.JWT_JWKS_URL, config.ENV.JWT_JWKS_FILE, nil)
+		if err != nil {
+			return fmt.Errorf("failed to configure %s token verifier: %w", algorithm, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := keySet.Refresh(ctx); err != nil {
+			return fmt.Errorf("failed to load JWKS: %w", err)
+		}
+
+		interval, err := strconv.Atoi(config.ENV.JWT_JWKS_REFRESH_INTERVAL_SECOND)
+		if err != nil || interval <= 0 {
+			interval = 3600
+		}
+
+		keySet.StartAutoRefresh(time.Duration(interval) * time.Second)
+		token.SetKeySet(keySet)
+
+		app.tokenKeySet = keySet
+
+		log.Info().Msgf("token verifier using %s with JWKS, refreshing every %ds", algorithm, interval)
+
+		return nil
+	}
+}
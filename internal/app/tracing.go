@@ -0,0 +1,25 @@
+package app
+
+import (
+	"context"
+
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/tracing"
+	"github.com/rs/zerolog/log"
+)
+
+func WithTracing() Option {
+	return func(app *App) error {
+
+		shutdown, err := tracing.Init(context.Background())
+		if err != nil {
+			return err
+		}
+
+		app.shutdownTracing = shutdown
+
+		log.Info().Msgf("tracing initialized with %s exporter", config.ENV.TRACING_EXPORTER)
+
+		return nil
+	}
+}
@@ -1,30 +1,84 @@
 package middlewares
 
 import (
-	"strings"
+	"context"
+	"errors"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/revandpratama/lognest/internal/modules/auth/dto"
+	"github.com/revandpratama/lognest/internal/modules/auth/usecase"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/logging"
 	"github.com/revandpratama/lognest/pkg/token"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
 )
 
-func AuthMiddleware() func(c *fiber.Ctx) error {
+type refreshResult struct {
+	response *dto.LoginResponse
+	claims   *token.CustomClaims
+}
+
+func AuthMiddleware(authUsecase usecase.AuthUsecase) func(c *fiber.Ctx) error {
+	// Parallel requests carrying the same expired session share a single refresh call. A refresh
+	// token presented after its rotation is rejected as reuse.
+	var refreshGroup singleflight.Group
+
 	return func(c *fiber.Ctx) error {
 
-		access_token := c.Cookies("access_token")
+		access_token := c.Cookies(token.AccessTokenCookieName)
 		if access_token == "" {
 			return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized, no token provided"}, nil)
 		}
 
-		parts := strings.Split(access_token, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
+		encryptedToken, err := token.ExtractBearer(access_token)
+		if err != nil {
 			return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized, invalid token format"}, nil)
 		}
 
-		encryptedToken := parts[1]
 		user, err := token.ValidateToken(encryptedToken)
 		if err != nil {
-			return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized, invalid token"}, nil)
+			// Copied because it outlives the request as a map key, and fasthttp reuses its buffers.
+			refresh_token := utils.CopyString(c.Cookies(token.RefreshTokenCookieName))
+			if !errors.Is(err, jwt.ErrTokenExpired) || refresh_token == "" {
+				return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized, invalid token"}, nil)
+			}
+
+			result, err, _ := refreshGroup.Do(refresh_token, func() (any, error) {
+				// Detached from the request so one cancelled caller cannot fail the others, but kept
+				// in the first caller's trace.
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(c.UserContext()), 5*time.Second)
+				defer cancel()
+
+				res, claims, err := authUsecase.RefreshExpiredToken(ctx, access_token, refresh_token)
+				if err != nil {
+					return nil, err
+				}
+
+				return &refreshResult{response: res, claims: claims}, nil
+			})
+			if err != nil {
+				return errorhandler.BuildError(c, err, nil)
+			}
+
+			refreshed := result.(*refreshResult)
+			token.SetTokenCookies(c, refreshed.response.Data.AccessToken, refreshed.response.Data.RefreshToken)
+
+			// Downstream handlers read the cookies again, so expose the new tokens to them.
+			c.Request().Header.SetCookie(token.AccessTokenCookieName, refreshed.response.Data.AccessToken)
+			c.Request().Header.SetCookie(token.RefreshTokenCookieName, refreshed.response.Data.RefreshToken)
+
+			user = refreshed.claims
+		}
+
+		if err := authUsecase.CheckSession(c.UserContext(), user, c.Get(fiber.HeaderUserAgent), c.IP()); err != nil {
+			if errorhandler.From(err).Status == fiber.StatusUnauthorized {
+				token.ClearTokenCookies(c)
+			}
+			return errorhandler.BuildError(c, err, nil)
 		}
 
 		c.Locals("userID", user.UserID)
@@ -34,6 +88,10 @@ func AuthMiddleware() func(c *fiber.Ctx) error {
 		c.Locals("sessionID", user.SessionID)
 		c.Locals("mfaCompleted", user.MFACompleted)
 
+		c.SetUserContext(logging.With(c.UserContext(), func(fields zerolog.Context) zerolog.Context {
+			return fields.Str("user_id", user.UserID)
+		}))
+
 		return c.Next()
 	}
 }
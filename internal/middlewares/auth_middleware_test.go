@@ -0,0 +1,201 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/internal/modules/auth/dto"
+	"github.com/revandpratama/lognest/internal/modules/auth/usecase"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/token"
+)
+
+// fakeAuth rotates refresh tokens the way the auth usecase does: each one is consumed by the
+// refresh that presents it, and presenting it again is rejected as reuse.
+type fakeAuth struct {
+	usecase.AuthUsecase
+
+	// release, when set, holds refreshes until it is closed.
+	release   chan struct{}
+	refreshes atomic.Int32
+
+	mu       sync.Mutex
+	consumed map[string]bool
+	revoked  map[string]bool
+}
+
+func newFakeAuth() *fakeAuth {
+	return &fakeAuth{consumed: make(map[string]bool), revoked: make(map[string]bool)}
+}
+
+func (a *fakeAuth) RefreshExpiredToken(ctx context.Context, accessToken string, refreshToken string) (*dto.LoginResponse, *token.CustomClaims, error) {
+	a.refreshes.Add(1)
+	if a.release != nil {
+		<-a.release
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.consumed[refreshToken] {
+		return nil, nil, errorhandler.UnauthorizedError{Message: "unauthorized, refresh token reuse detected"}
+	}
+	a.consumed[refreshToken] = true
+
+	return &dto.LoginResponse{Data: dto.TokenResponse{AccessToken: "Bearer rotated", RefreshToken: refreshToken + "-next"}},
+		&token.CustomClaims{UserID: "user-1", SessionID: "session-1"}, nil
+}
+
+func (a *fakeAuth) CheckSession(ctx context.Context, claims *token.CustomClaims, userAgent string, ipAddress string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.revoked[claims.SessionID] {
+		// Wrapped like errors passed up through the usecase, which must not hide the status.
+		return fmt.Errorf("check session: %w", errorhandler.UnauthorizedError{Message: "unauthorized, session has been revoked"})
+	}
+	return nil
+}
+
+// useHS256 signs and verifies tokens with a test secret for the duration of t.
+func useHS256(t *testing.T) {
+	t.Helper()
+
+	previousAlgorithm, previousSecret := config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET
+	config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET = "HS256", "test-secret"
+	t.Cleanup(func() {
+		config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET = previousAlgorithm, previousSecret
+	})
+}
+
+func accessTokenCookie(t *testing.T, sessionID string, expiresIn time.Duration) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, token.CustomClaims{
+		UserID:           "user-1",
+		SessionID:        sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn))},
+	}).SignedString([]byte(config.ENV.JWT_SECRET))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return token.AccessTokenCookieName + "=Bearer " + signed
+}
+
+func authApp(auth usecase.AuthUsecase) *fiber.App {
+	app := fiber.New()
+	app.Get("/", AuthMiddleware(auth), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func get(t *testing.T, app *fiber.App, cookies string) int {
+	t.Helper()
+
+	resp := do(t, app, cookies)
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func do(t *testing.T, app *fiber.App, cookies string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderCookie, cookies)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Errorf("app.Test: %v", err)
+		return nil
+	}
+	return resp
+}
+
+func TestAuthMiddlewareSharesConcurrentRefreshes(t *testing.T) {
+	useHS256(t)
+
+	auth := newFakeAuth()
+	auth.release = make(chan struct{})
+	app := authApp(auth)
+	cookies := accessTokenCookie(t, "session-1", -time.Minute) + "; " + token.RefreshTokenCookieName + "=refresh-1"
+
+	const requests = 5
+	statuses := make(chan int, requests)
+	for range requests {
+		go func() { statuses <- get(t, app, cookies) }()
+	}
+
+	// Give every request time to join the refresh in flight before letting it finish.
+	time.Sleep(100 * time.Millisecond)
+	close(auth.release)
+
+	for range requests {
+		if status := <-statuses; status != fiber.StatusOK {
+			t.Errorf("status = %d, want %d", status, fiber.StatusOK)
+		}
+	}
+	if got := auth.refreshes.Load(); got != 1 {
+		t.Errorf("refreshes = %d, want 1 for concurrent requests", got)
+	}
+}
+
+func TestAuthMiddlewareRejectsReplayedRefreshToken(t *testing.T) {
+	useHS256(t)
+
+	app := authApp(newFakeAuth())
+	cookies := accessTokenCookie(t, "session-1", -time.Minute) + "; " + token.RefreshTokenCookieName + "=refresh-1"
+
+	if status := get(t, app, cookies); status != fiber.StatusOK {
+		t.Fatalf("first refresh status = %d, want %d", status, fiber.StatusOK)
+	}
+
+	// Once rotated, the old refresh token must not yield tokens again.
+	if status := get(t, app, cookies); status != fiber.StatusUnauthorized {
+		t.Errorf("replayed refresh status = %d, want %d", status, fiber.StatusUnauthorized)
+	}
+}
+
+func TestAuthMiddlewareRejectsRevokedSession(t *testing.T) {
+	useHS256(t)
+
+	auth := newFakeAuth()
+	app := authApp(auth)
+	cookies := accessTokenCookie(t, "session-1", time.Minute)
+
+	if status := get(t, app, cookies); status != fiber.StatusOK {
+		t.Fatalf("status before revocation = %d, want %d", status, fiber.StatusOK)
+	}
+
+	auth.mu.Lock()
+	auth.revoked["session-1"] = true
+	auth.mu.Unlock()
+
+	resp := do(t, app, cookies)
+	if resp == nil {
+		return
+	}
+	if resp.StatusCode != fiber.StatusUnauthorized {
+		t.Errorf("status after revocation = %d, want %d", resp.StatusCode, fiber.StatusUnauthorized)
+	}
+
+	cleared := map[string]bool{}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Expires.Before(time.Now()) {
+			cleared[cookie.Name] = true
+		}
+	}
+	for _, name := range []string{token.AccessTokenCookieName, token.RefreshTokenCookieName} {
+		if !cleared[name] {
+			t.Errorf("%s cookie not cleared for a revoked session", name)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/csrf"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/token"
+)
+
+// CSRFMiddleware enforces the double-submit token on state-changing requests that carry auth
+// cookies. Every authenticated request does, since AuthMiddleware only reads the cookies, so an
+// Authorization header earns no exemption.
+func CSRFMiddleware() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+			return c.Next()
+		}
+
+		if c.Cookies(token.AccessTokenCookieName) == "" && c.Cookies(token.RefreshTokenCookieName) == "" {
+			return c.Next()
+		}
+
+		if !csrf.Verify(c.Cookies(csrf.CookieName), c.Get(csrf.HeaderName)) {
+			return errorhandler.BuildError(c, errorhandler.ForbiddenError{Message: "invalid or missing CSRF token"}, nil)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/csrf"
+	"github.com/revandpratama/lognest/pkg/token"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	authCookie := token.AccessTokenCookieName + "=Bearer abc"
+
+	tests := []struct {
+		name    string
+		method  string
+		cookies string
+		headers map[string]string
+		want    int
+	}{
+		{name: "safe method", method: fiber.MethodGet, cookies: authCookie, want: fiber.StatusOK},
+		{name: "no auth cookies", method: fiber.MethodPost, want: fiber.StatusOK},
+		{name: "missing token", method: fiber.MethodPost, cookies: authCookie, want: fiber.StatusForbidden},
+		{
+			name:    "mismatched token",
+			method:  fiber.MethodDelete,
+			cookies: authCookie + "; " + csrf.CookieName + "=one",
+			headers: map[string]string{csrf.HeaderName: "two"},
+			want:    fiber.StatusForbidden,
+		},
+		{
+			name:    "matching token",
+			method:  fiber.MethodPatch,
+			cookies: authCookie + "; " + csrf.CookieName + "=one",
+			headers: map[string]string{csrf.HeaderName: "one"},
+			want:    fiber.StatusOK,
+		},
+		{
+			name:    "refresh cookie alone",
+			method:  fiber.MethodPost,
+			cookies: token.RefreshTokenCookieName + "=abc",
+			want:    fiber.StatusForbidden,
+		},
+		{
+			// Browsers attach the cookies whatever else the request carries, so an Authorization
+			// header must not exempt it.
+			name:    "bearer header with cookies",
+			method:  fiber.MethodPost,
+			cookies: authCookie,
+			headers: map[string]string{fiber.HeaderAuthorization: "Bearer abc"},
+			want:    fiber.StatusForbidden,
+		},
+	}
+
+	app := fiber.New()
+	app.Use(CSRFMiddleware())
+	app.All("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.cookies != "" {
+				req.Header.Set(fiber.HeaderCookie, tt.cookies)
+			}
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/revandpratama/lognest/pkg/metrics"
+)
+
+// MetricsMiddleware counts and times every request by method, route template and status. It must
+// be registered after TracingMiddleware and RequestLoggerMiddleware and ahead of every other
+// middleware, since it renders errors with the ErrorHandler itself to record the final status.
+// Requests answered by a middleware before reaching a handler, such as a rejected token, are
+// labeled with the middleware's path prefix, and requests that match no route as "unmatched", so
+// raw paths never become label values.
+func MetricsMiddleware() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		startedAt := time.Now()
+
+		route := ""
+		if err := c.Next(); err != nil {
+			// The router reports requests no route matches with a plain fiber.Error.
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) && (fiberErr.Code == fiber.StatusNotFound || fiberErr.Code == fiber.StatusMethodNotAllowed) {
+				route = "unmatched"
+			}
+
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		if route == "" {
+			route = c.Route().Path
+		}
+
+		// The method is copied because fasthttp reuses its buffer for the next request.
+		labels := []string{utils.CopyString(c.Method()), route, strconv.Itoa(c.Response().StatusCode())}
+
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(startedAt).Seconds())
+
+		return nil
+	}
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+)
+
+// RequireMFA guards sensitive routes so they are only reachable from sessions that completed MFA.
+// It must run after AuthMiddleware, which populates the "mfaCompleted" local from the token claims.
+func RequireMFA() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+
+		mfaCompleted, ok := c.Locals("mfaCompleted").(bool)
+		if !ok || !mfaCompleted {
+			return errorhandler.BuildError(c, errorhandler.MFARequiredError{Message: "multi-factor authentication is required for this operation"}, nil)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/logging"
+)
+
+// RecoverMiddleware turns a panic in a later handler into an internal error for the ErrorHandler,
+// logging the stack trace with the request logger, so that one bad request cannot take down the server.
+func RecoverMiddleware() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) (err error) {
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			logging.Ctx(c.UserContext()).Error().
+				Interface("panic", recovered).
+				Bytes("stack", debug.Stack()).
+				Msg("recovered from panic")
+
+			err = errorhandler.Wrap(fmt.Errorf("panic: %v", recovered), fiber.StatusInternalServerError, errorhandler.CodeInternal, "internal server error")
+		}()
+
+		return c.Next()
+	}
+}
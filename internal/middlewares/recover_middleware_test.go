@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler})
+	app.Use(RecoverMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		panic("secret state")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusInternalServerError)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentType); got != errorhandler.ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, errorhandler.ProblemContentType)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	var problem errorhandler.Problem
+	if err := json.Unmarshal(body, &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Code != errorhandler.CodeInternal {
+		t.Errorf("code = %q, want %q", problem.Code, errorhandler.CodeInternal)
+	}
+	if strings.Contains(string(body), "secret state") {
+		t.Errorf("body %s leaks the panic value", body)
+	}
+}
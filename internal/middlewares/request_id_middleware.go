@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware echoes the client's X-Request-ID, so a request can be followed from a proxy or
+// frontend into our logs, or assigns a new one when it is missing or not a plausible ID. The ID is
+// sent back on the response, where errorhandler.RequestID finds it.
+func RequestIDMiddleware() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+
+		requestID := c.Get(fiber.HeaderXRequestID)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(fiber.HeaderXRequestID, requestID)
+
+		return c.Next()
+	}
+}
+
+// validRequestID accepts printable ASCII without spaces, which keeps log lines and headers intact.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] <= ' ' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		requestID string
+		want      bool
+	}{
+		{requestID: "", want: false},
+		{requestID: "0b6e5c1e-7f3a-4d2b-9a8c-1f2e3d4c5b6a", want: true},
+		{requestID: "frontend:42/retry", want: true},
+		{requestID: strings.Repeat("a", maxRequestIDLength), want: true},
+		{requestID: strings.Repeat("a", maxRequestIDLength+1), want: false},
+		{requestID: "with space", want: false},
+		{requestID: "line\nbreak", want: false},
+		{requestID: "tab\there", want: false},
+		{requestID: "del\x7f", want: false},
+		{requestID: "ünïcode", want: false},
+	}
+	for _, tt := range tests {
+		if got := validRequestID(tt.requestID); got != tt.want {
+			t.Errorf("validRequestID(%q) = %v, want %v", tt.requestID, got, tt.want)
+		}
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestIDMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	requestID := func(header string) string {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set(fiber.HeaderXRequestID, header)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		return resp.Header.Get(fiber.HeaderXRequestID)
+	}
+
+	if got := requestID("frontend-42"); got != "frontend-42" {
+		t.Errorf("X-Request-ID = %q, want the client's ID echoed", got)
+	}
+	for _, header := range []string{"", strings.Repeat("a", maxRequestIDLength+1)} {
+		if _, err := uuid.Parse(requestID(header)); err != nil {
+			t.Errorf("X-Request-ID for %q is not a generated UUID: %v", header, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/logging"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestLoggerMiddleware puts a logger carrying the request ID, method, path, route and trace ID
+// on the user context, where logging.Ctx finds it, and writes one access log line per request once
+// the response is final. It must be registered after RequestIDMiddleware and ahead of
+// MetricsMiddleware, which renders errors.
+func RequestLoggerMiddleware() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		startedAt := time.Now()
+
+		route := &routeHook{c: c}
+
+		fields := logging.Ctx(c.UserContext()).With().
+			Str("request_id", errorhandler.RequestID(c)).
+			Str("method", c.Method()).
+			Str("path", c.Path())
+		if spanContext := trace.SpanContextFromContext(c.UserContext()); spanContext.IsValid() {
+			fields = fields.Str("trace_id", spanContext.TraceID().String())
+		}
+		logger := fields.Logger().Hook(route)
+
+		c.SetUserContext(logger.WithContext(c.UserContext()))
+
+		err := c.Next()
+
+		route.detach()
+
+		status := c.Response().StatusCode()
+
+		// Later middlewares may have added fields, such as the user ID, to the context logger.
+		var event *zerolog.Event
+		switch accessLogger := logging.Ctx(c.UserContext()); {
+		case status >= fiber.StatusInternalServerError:
+			event = accessLogger.Error()
+		case status >= fiber.StatusBadRequest:
+			event = accessLogger.Warn()
+		default:
+			event = accessLogger.Info()
+		}
+
+		event.
+			Int("status", status).
+			Dur("latency_ms", time.Since(startedAt)).
+			Str("ip", c.IP()).
+			Int("bytes_out", len(c.Response().Body())).
+			Str("user_agent", c.Get(fiber.HeaderUserAgent)).
+			Msg("request completed")
+
+		return err
+	}
+}
+
+// routeHook adds the matched route to every entry of a request logger. The route is only known
+// once the router reaches the handler, after the logger was created, so it is read when logging.
+// detach freezes the final route, since the Ctx is reused once the request is done and the logger
+// may outlive it in a detached context.
+type routeHook struct {
+	mu    sync.Mutex
+	c     *fiber.Ctx
+	route string
+}
+
+func (h *routeHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.c != nil {
+		e.Str("route", h.c.Route().Path)
+		return
+	}
+	e.Str("route", h.route)
+}
+
+func (h *routeHook) detach() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.route = h.c.Route().Path
+	h.c = nil
+}
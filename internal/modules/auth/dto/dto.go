@@ -1,8 +1,10 @@
 package dto
 
+import "time"
+
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
 }
 
 type LoginResponse struct {
@@ -17,10 +19,27 @@ type TokenResponse struct {
 }
 
 type RegisterRequest struct {
-	Email           string `json:"email"`
-	FirstName       string `json:"first_name"`
-	LastName        string `json:"last_name"`
-	AvatarPath      string `json:"avatar_path"`
-	Password        string `json:"password"`
-	ConfirmPassword string `json:"confirm_password"`
+	Email           string `json:"email" validate:"required,email"`
+	FirstName       string `json:"first_name" validate:"required,max=255"`
+	LastName        string `json:"last_name" validate:"max=255"`
+	AvatarPath      string `json:"avatar_path" validate:"omitempty,filepath_prefix,max=500"`
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+type LogoutOthersResponse struct {
+	RevokedSessions int `json:"revoked_sessions"`
+}
+
+type CSRFTokenResponse struct {
+	CSRFToken string `json:"csrf_token"`
 }
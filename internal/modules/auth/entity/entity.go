@@ -32,3 +32,20 @@ func (p *Auth) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// Session tracks an Auth4me session (the "sid" claim) seen by lognest so it can be listed and revoked.
+type Session struct {
+	ID         string     `gorm:"type:varchar(255);primary_key" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	UserAgent  string     `gorm:"type:varchar(500)" json:"user_agent"`
+	IPAddress  string     `gorm:"type:varchar(64)" json:"ip_address"`
+	LastSeenAt time.Time  `gorm:"not null" json:"last_seen_at"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"not null" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"not null" json:"updated_at"`
+}
+
+// TableName sets the table name for the Session.
+func (Session) TableName() string {
+	return fmt.Sprintf("%s.%s", config.ENV.LOGNEST_SCHEMA, "sessions")
+}
@@ -5,11 +5,14 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/revandpratama/lognest/config"
+	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/internal/modules/auth/dto"
 	"github.com/revandpratama/lognest/internal/modules/auth/usecase"
+	"github.com/revandpratama/lognest/pkg/csrf"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
 	"github.com/revandpratama/lognest/pkg/response"
+	"github.com/revandpratama/lognest/pkg/token"
+	"github.com/revandpratama/lognest/pkg/validation"
 )
 
 // AuthHandler defines the HTTP handler interface for a Auth.
@@ -18,6 +21,9 @@ type AuthHandler interface {
 	Register(c *fiber.Ctx) error
 	RefreshToken(c *fiber.Ctx) error
 	Logout(c *fiber.Ctx) error
+	FindSessions(c *fiber.Ctx) error
+	LogoutOtherSessions(c *fiber.Ctx) error
+	CSRFToken(c *fiber.Ctx) error
 }
 
 type authHandler struct {
@@ -30,7 +36,7 @@ func NewAuthHandler(usecase usecase.AuthUsecase) AuthHandler {
 }
 
 func (u *authHandler) Login(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	var loginRequest dto.LoginRequest
@@ -38,45 +44,21 @@ func (u *authHandler) Login(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
+	if errs := validation.Struct(c, &loginRequest); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
 	res, err := u.usecase.Login(ctx, &loginRequest)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	accessTokenCookie := &fiber.Cookie{
-		Name:     "access_token",
-		Value:    res.Data.AccessToken,
-		Expires:  time.Now().Add(time.Minute * 5),
-		HTTPOnly: true,
-	}
-
-	if config.ENV.APP_ENV == "production" {
-		accessTokenCookie.SameSite = "None"
-		accessTokenCookie.Domain = ".revandpratama.com"
-		accessTokenCookie.Secure = true
-	}
-
-	c.Cookie(accessTokenCookie)
-
-	refreshTokenCookie := &fiber.Cookie{
-		Name:     "refresh_token",
-		Value:    res.Data.RefreshToken,
-		Expires:  time.Now().Add(time.Hour * 24),
-		HTTPOnly: true,
-	}
-
-	if config.ENV.APP_ENV == "production" {
-		refreshTokenCookie.SameSite = "None"
-		refreshTokenCookie.Domain = ".revandpratama.com"
-		refreshTokenCookie.Secure = true
-	}
-
-	c.Cookie(refreshTokenCookie)
+	token.SetTokenCookies(c, res.Data.AccessToken, res.Data.RefreshToken)
 	return response.Success(c, fiber.StatusOK, "login success", nil)
 }
 
 func (u *authHandler) Register(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	var registerRequest dto.RegisterRequest
@@ -84,6 +66,10 @@ func (u *authHandler) Register(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
+	if errs := validation.Struct(c, &registerRequest); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
 	data, err := u.usecase.Register(ctx, &registerRequest)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
@@ -93,15 +79,15 @@ func (u *authHandler) Register(c *fiber.Ctx) error {
 }
 
 func (u *authHandler) RefreshToken(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	accessToken := c.Cookies("access_token")
+	accessToken := c.Cookies(token.AccessTokenCookieName)
 	if accessToken == "" {
 		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized, no access token provided"}, nil)
 	}
 
-	refreshToken := c.Cookies("refresh_token")
+	refreshToken := c.Cookies(token.RefreshTokenCookieName)
 	if refreshToken == "" {
 		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized, no refresh token provided"}, nil)
 	}
@@ -111,67 +97,85 @@ func (u *authHandler) RefreshToken(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	accessTokenCookie := &fiber.Cookie{
-		Name:     "access_token",
-		Value:    res.Data.AccessToken,
-		Expires:  time.Now().Add(time.Minute * 5),
-		HTTPOnly: true,
-	}
+	token.SetTokenCookies(c, res.Data.AccessToken, res.Data.RefreshToken)
+
+	return response.Success(c, fiber.StatusOK, "refresh token success", nil)
+}
+
+func (u *authHandler) Logout(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
+	defer cancel()
+
+	accessToken := c.Cookies(token.AccessTokenCookieName)
+	refreshToken := c.Cookies(token.RefreshTokenCookieName)
+
+	// The cookies go even when revoking the session fails, so the client is logged out regardless.
+	token.ClearTokenCookies(c)
 
-	if config.ENV.APP_ENV == "production" {
-		accessTokenCookie.SameSite = "None"
-		accessTokenCookie.Domain = ".revandpratama.com"
-		accessTokenCookie.Secure = true
+	if accessToken != "" {
+		if err := u.usecase.Logout(ctx, accessToken, refreshToken); err != nil {
+			return errorhandler.BuildError(c, err, nil)
+		}
 	}
 
-	c.Cookie(accessTokenCookie)
+	return response.Success(c, fiber.StatusOK, "logout success", nil)
+}
+
+func (u *authHandler) FindSessions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
+	defer cancel()
 
-	refreshTokenCookie := &fiber.Cookie{
-		Name:     "refresh_token",
-		Value:    res.Data.RefreshToken,
-		Expires:  time.Now().Add(time.Hour * 24),
-		HTTPOnly: true,
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
 	}
 
-	if config.ENV.APP_ENV == "production" {
-		refreshTokenCookie.SameSite = "None"
-		refreshTokenCookie.Domain = ".revandpratama.com"
-		refreshTokenCookie.Secure = true
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
 	}
 
-	c.Cookie(refreshTokenCookie)
+	sessionID, _ := c.Locals("sessionID").(string)
 
-	return response.Success(c, fiber.StatusOK, "refresh token success", nil)
+	sessions, err := u.usecase.FindActiveSessions(ctx, userID, sessionID)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	return response.Success(c, fiber.StatusOK, "sessions found", sessions)
 }
 
-func (u *authHandler) Logout(c *fiber.Ctx) error {
-	accessTokenCookie := &fiber.Cookie{
-		Name:     "access_token",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour), // Set expiration to the past
-		HTTPOnly: true,
-		Path:     "/",
+func (u *authHandler) LogoutOtherSessions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
+	defer cancel()
+
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
 	}
 
-	if config.ENV.APP_ENV == "production" {
-		accessTokenCookie.SameSite = "None"
-		accessTokenCookie.Secure = true
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
 	}
 
-	c.Cookie(accessTokenCookie)
+	sessionID, _ := c.Locals("sessionID").(string)
 
-	refreshTokenCookie := &fiber.Cookie{
-		Name:     "refresh_token",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour),
-		HTTPOnly: true,
-		Path:     "/",
+	res, err := u.usecase.LogoutOtherSessions(ctx, userID, sessionID, c.Cookies(token.AccessTokenCookieName))
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
 	}
 
-	if config.ENV.APP_ENV == "production" {
-		refreshTokenCookie.SameSite = "None"
-		refreshTokenCookie.Secure = true
+	return response.Success(c, fiber.StatusOK, "other sessions logged out", res)
+}
+
+func (u *authHandler) CSRFToken(c *fiber.Ctx) error {
+	csrfToken, err := csrf.Generate()
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.InternalServerError{Message: "failed to generate csrf token"}, nil)
 	}
-	c.Cookie(refreshTokenCookie)
-	return response.Success(c, fiber.StatusOK, "logout success", nil)
+
+	csrf.SetCookie(c, csrfToken)
+
+	return response.Success(c, fiber.StatusOK, "csrf token issued", dto.CSRFTokenResponse{CSRFToken: csrfToken})
 }
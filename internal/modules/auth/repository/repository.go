@@ -1,12 +1,22 @@
 package repository
 
 import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/internal/modules/auth/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AuthRepository defines the interface for database operations for a Auth.
 type AuthRepository interface {
-	
+	FindSessionByID(ctx context.Context, sessionID string) (*entity.Session, error)
+	FindActiveSessionsByUserID(ctx context.Context, userID uuid.UUID, since time.Time) ([]entity.Session, error)
+	UpsertSession(ctx context.Context, session *entity.Session) error
+	RevokeSession(ctx context.Context, sessionID string, userID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]string, error)
 }
 
 type authRepository struct {
@@ -18,4 +28,66 @@ func NewAuthRepository(db *gorm.DB) AuthRepository {
 	return &authRepository{db: db}
 }
 
-// NOTE: The following are example implementations. You will need to adjust them.
+func (r *authRepository) FindSessionByID(ctx context.Context, sessionID string) (*entity.Session, error) {
+	var session entity.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", sessionID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *authRepository) FindActiveSessionsByUserID(ctx context.Context, userID uuid.UUID, since time.Time) ([]entity.Session, error) {
+	var sessions []entity.Session
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND last_seen_at >= ?", userID, since).
+		Order("last_seen_at desc").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *authRepository) UpsertSession(ctx context.Context, session *entity.Session) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_agent", "ip_address", "last_seen_at", "updated_at"}),
+	}).Create(session).Error
+}
+
+func (r *authRepository) RevokeSession(ctx context.Context, sessionID string, userID uuid.UUID) error {
+	now := time.Now()
+	session := &entity.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		LastSeenAt: now,
+		RevokedAt:  &now,
+	}
+
+	// Sessions that were never recorded still need to land on the revocation list.
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at", "updated_at"}),
+	}).Create(session).Error
+}
+
+func (r *authRepository) RevokeOtherSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]string, error) {
+	var sessionIDs []string
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.WithContext(ctx).Model(&entity.Session{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, currentSessionID).
+			Pluck("id", &sessionIDs).Error; err != nil {
+			return err
+		}
+
+		if len(sessionIDs) == 0 {
+			return nil
+		}
+
+		return tx.WithContext(ctx).Model(&entity.Session{}).
+			Where("id IN ?", sessionIDs).
+			Update("revoked_at", time.Now()).Error
+	})
+
+	return sessionIDs, err
+}
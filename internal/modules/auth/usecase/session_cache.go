@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// sessionCacheTTL bounds how long a revocation made on another instance can go unnoticed.
+	sessionCacheTTL = 30 * time.Second
+	// sessionTouchInterval throttles last_seen_at writes for active sessions.
+	sessionTouchInterval = time.Minute
+)
+
+type sessionCacheEntry struct {
+	revoked   bool
+	checkedAt time.Time
+}
+
+// sessionCache keeps the revocation state of recently seen sessions in memory so
+// AuthMiddleware does not hit the database on every request.
+type sessionCache struct {
+	mu        sync.RWMutex
+	entries   map[string]sessionCacheEntry
+	lastSweep time.Time
+}
+
+func newSessionCache() *sessionCache {
+	return &sessionCache{entries: make(map[string]sessionCacheEntry)}
+}
+
+func (c *sessionCache) get(sessionID string) (sessionCacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[sessionID]
+	if !ok || time.Since(entry.checkedAt) > sessionCacheTTL {
+		return sessionCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *sessionCache) set(sessionID string, entry sessionCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[sessionID] = entry
+	c.evictExpiredLocked()
+}
+
+func (c *sessionCache) revoke(sessionIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for _, sessionID := range sessionIDs {
+		c.entries[sessionID] = sessionCacheEntry{revoked: true, checkedAt: now}
+	}
+}
+
+func (c *sessionCache) evictExpiredLocked() {
+	if time.Since(c.lastSweep) < sessionCacheTTL {
+		return
+	}
+	c.lastSweep = time.Now()
+
+	for sessionID, entry := range c.entries {
+		if time.Since(entry.checkedAt) > sessionCacheTTL {
+			delete(c.entries, sessionID)
+		}
+	}
+}
@@ -4,18 +4,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/config"
 	"github.com/revandpratama/lognest/internal/modules/auth/dto"
+	"github.com/revandpratama/lognest/internal/modules/auth/entity"
 	"github.com/revandpratama/lognest/internal/modules/auth/repository"
 	userProfileEntity "github.com/revandpratama/lognest/internal/modules/user-profile/entity"
 	userProfileRepository "github.com/revandpratama/lognest/internal/modules/user-profile/repository"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/logging"
+	"github.com/revandpratama/lognest/pkg/token"
+	"gorm.io/gorm"
 )
 
 // AuthUsecase defines the business logic interface for a Auth.
@@ -23,12 +28,18 @@ type AuthUsecase interface {
 	Login(ctx context.Context, loginRequest *dto.LoginRequest) (*dto.LoginResponse, error)
 	Register(ctx context.Context, registerRequest *dto.RegisterRequest) (map[string]any, error)
 	RefreshToken(ctx context.Context, accessToken string, refreshToken string) (*dto.LoginResponse, error)
+	RefreshExpiredToken(ctx context.Context, accessToken string, refreshToken string) (*dto.LoginResponse, *token.CustomClaims, error)
+	Logout(ctx context.Context, accessToken string, refreshToken string) error
+	CheckSession(ctx context.Context, claims *token.CustomClaims, userAgent string, ipAddress string) error
+	FindActiveSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]dto.SessionResponse, error)
+	LogoutOtherSessions(ctx context.Context, userID uuid.UUID, currentSessionID string, accessToken string) (*dto.LogoutOthersResponse, error)
 }
 
 type authUsecase struct {
 	repo            repository.AuthRepository
 	userProfileRepo userProfileRepository.UserProfileRepository
 	httpClient      *http.Client
+	sessions        *sessionCache
 }
 
 // NewAuthUsecase creates a new instance of AuthUsecase.
@@ -37,6 +48,7 @@ func NewAuthUsecase(repo repository.AuthRepository, userProfileRepo userProfileR
 		repo:            repo,
 		httpClient:      httpClient,
 		userProfileRepo: userProfileRepo,
+		sessions:        newSessionCache(),
 	}
 }
 
@@ -49,7 +61,13 @@ func (u *authUsecase) Login(ctx context.Context, loginRequest *dto.LoginRequest)
 
 	var url = fmt.Sprintf("%s/api/auth/login", config.ENV.AUTH4ME_URL)
 
-	resp, err := u.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, errorhandler.InternalServerError{Message: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := u.httpClient.Do(req)
 	if err != nil {
 		return nil, errorhandler.InternalServerError{Message: err.Error()}
 	}
@@ -75,7 +93,13 @@ func (u *authUsecase) Register(ctx context.Context, registerRequest *dto.Registe
 	}
 
 	url := fmt.Sprintf("%s/api/auth/register", config.ENV.AUTH4ME_URL)
-	resp, err := u.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, errorhandler.InternalServerError{Message: "failed to build request: " + err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := u.httpClient.Do(req)
 	if err != nil {
 		return nil, errorhandler.InternalServerError{Message: "http request failed: " + err.Error()}
 	}
@@ -129,7 +153,7 @@ func (u *authUsecase) Register(ctx context.Context, registerRequest *dto.Registe
 
 	_, err = u.userProfileRepo.Create(ctx, newUserProfile)
 	if err != nil {
-		log.Printf("CRITICAL: Failed to create user profile for user ID %s after successful registration", userID)
+		logging.Ctx(ctx).Error().Err(err).Str("registered_user_id", userID.String()).Msg("failed to create user profile after successful registration")
 		return nil, errorhandler.InternalServerError{Message: "failed to create user profile"}
 	}
 
@@ -138,9 +162,19 @@ func (u *authUsecase) Register(ctx context.Context, registerRequest *dto.Registe
 
 func (u *authUsecase) RefreshToken(ctx context.Context, accessToken string, refreshToken string) (*dto.LoginResponse, error) {
 
+	if claims, err := parseBearerExpired(accessToken); err == nil && claims.SessionID != "" {
+		revoked, err := u.isSessionRevoked(ctx, claims.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, errorhandler.UnauthorizedError{Message: "unauthorized, session has been revoked"}
+		}
+	}
+
 	var url = fmt.Sprintf("%s/api/auth/refresh-token", config.ENV.AUTH4ME_URL)
 
-	req, err := http.NewRequest(http.MethodPost, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return nil, errorhandler.InternalServerError{Message: err.Error()}
 	}
@@ -167,3 +201,227 @@ func (u *authUsecase) RefreshToken(ctx context.Context, accessToken string, refr
 
 	return &loginResponse, nil
 }
+
+// RefreshExpiredToken refreshes a session whose access token has expired. The expired
+// token must still carry a valid signature so that only tokens we issued can be refreshed.
+// It returns the new tokens together with the claims of the new access token.
+func (u *authUsecase) RefreshExpiredToken(ctx context.Context, accessToken string, refreshToken string) (*dto.LoginResponse, *token.CustomClaims, error) {
+
+	if _, err := parseBearerExpired(accessToken); err != nil {
+		return nil, nil, errorhandler.UnauthorizedError{Message: "unauthorized, invalid token"}
+	}
+
+	res, err := u.RefreshToken(ctx, accessToken, refreshToken)
+	if err != nil {
+		if _, ok := err.(errorhandler.UnauthorizedError); ok {
+			return nil, nil, err
+		}
+		return nil, nil, errorhandler.UnauthorizedError{Message: "unauthorized, failed to refresh token"}
+	}
+
+	newToken, err := token.ExtractBearer(res.Data.AccessToken)
+	if err != nil {
+		return nil, nil, errorhandler.InternalServerError{Message: "auth service returned an invalid token format"}
+	}
+
+	claims, err := token.ValidateToken(newToken)
+	if err != nil {
+		return nil, nil, errorhandler.InternalServerError{Message: "auth service returned an invalid token"}
+	}
+
+	return res, claims, nil
+}
+
+// Logout revokes the session behind the given tokens, both in Auth4me and in the local
+// revocation list. The upstream call is best effort; the local revocation always applies.
+func (u *authUsecase) Logout(ctx context.Context, accessToken string, refreshToken string) error {
+
+	claims, err := parseBearerExpired(accessToken)
+	if err != nil {
+		// Nothing we can revoke; the handler still clears the cookies.
+		return nil
+	}
+
+	var url = fmt.Sprintf("%s/api/auth/logout", config.ENV.AUTH4ME_URL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", accessToken)
+		req.Header.Set("X-Refresh-Token", refreshToken)
+
+		resp, err := u.httpClient.Do(req)
+		if err != nil {
+			logging.Ctx(ctx).Warn().Err(err).Str("session_id", claims.SessionID).Msg("failed to revoke session upstream")
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				logging.Ctx(ctx).Warn().Int("upstream_status", resp.StatusCode).Str("session_id", claims.SessionID).Msg("failed to revoke session upstream")
+			}
+		}
+	}
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return errorhandler.BadRequestError{Message: "invalid user ID in token"}
+	}
+
+	if err := u.repo.RevokeSession(ctx, claims.SessionID, userID); err != nil {
+		return errorhandler.InternalServerError{Message: err.Error()}
+	}
+	u.sessions.revoke(claims.SessionID)
+
+	return nil
+}
+
+// CheckSession rejects revoked sessions and records the session as recently seen.
+func (u *authUsecase) CheckSession(ctx context.Context, claims *token.CustomClaims, userAgent string, ipAddress string) error {
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	if entry, ok := u.sessions.get(claims.SessionID); ok {
+		if entry.revoked {
+			return errorhandler.UnauthorizedError{Message: "unauthorized, session has been revoked"}
+		}
+		return nil
+	}
+
+	session, err := u.repo.FindSessionByID(ctx, claims.SessionID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errorhandler.InternalServerError{Message: err.Error()}
+	}
+
+	if session != nil && session.RevokedAt != nil {
+		u.sessions.revoke(claims.SessionID)
+		return errorhandler.UnauthorizedError{Message: "unauthorized, session has been revoked"}
+	}
+
+	if session == nil || time.Since(session.LastSeenAt) > sessionTouchInterval {
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			return errorhandler.UnauthorizedError{Message: "unauthorized, invalid user ID in token"}
+		}
+
+		if err := u.repo.UpsertSession(ctx, &entity.Session{
+			ID:         claims.SessionID,
+			UserID:     userID,
+			UserAgent:  truncate(userAgent, 500),
+			IPAddress:  truncate(ipAddress, 64),
+			LastSeenAt: time.Now(),
+		}); err != nil {
+			return errorhandler.InternalServerError{Message: err.Error()}
+		}
+	}
+
+	u.sessions.set(claims.SessionID, sessionCacheEntry{checkedAt: time.Now()})
+
+	return nil
+}
+
+func (u *authUsecase) FindActiveSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]dto.SessionResponse, error) {
+
+	// A session unseen for longer than the refresh token lifetime cannot be resumed.
+	since := time.Now().Add(-token.RefreshTokenCookieTTL)
+
+	sessions, err := u.repo.FindActiveSessionsByUserID(ctx, userID, since)
+	if err != nil {
+		return nil, errorhandler.InternalServerError{Message: err.Error()}
+	}
+
+	res := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, dto.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+
+	return res, nil
+}
+
+// LogoutOtherSessions revokes every other session of the user, locally and in Auth4me so their
+// refresh tokens stop working too. As in Logout, the upstream calls are best effort.
+func (u *authUsecase) LogoutOtherSessions(ctx context.Context, userID uuid.UUID, currentSessionID string, accessToken string) (*dto.LogoutOthersResponse, error) {
+
+	if currentSessionID == "" {
+		return nil, errorhandler.BadRequestError{Message: "current token has no session ID"}
+	}
+
+	sessionIDs, err := u.repo.RevokeOtherSessions(ctx, userID, currentSessionID)
+	if err != nil {
+		return nil, errorhandler.InternalServerError{Message: err.Error()}
+	}
+	u.sessions.revoke(sessionIDs...)
+
+	for _, sessionID := range sessionIDs {
+		u.revokeUpstreamSession(ctx, sessionID, accessToken)
+	}
+
+	return &dto.LogoutOthersResponse{RevokedSessions: len(sessionIDs)}, nil
+}
+
+// revokeUpstreamSession asks Auth4me to revoke another session of the caller, authorized by the
+// caller's own access token. Failures are logged, not returned.
+func (u *authUsecase) revokeUpstreamSession(ctx context.Context, sessionID string, accessToken string) {
+	var url = fmt.Sprintf("%s/api/auth/sessions/%s", config.ENV.AUTH4ME_URL, sessionID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		logging.Ctx(ctx).Warn().Err(err).Str("session_id", sessionID).Msg("failed to revoke session upstream")
+		return
+	}
+	req.Header.Set("Authorization", accessToken)
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		logging.Ctx(ctx).Warn().Err(err).Str("session_id", sessionID).Msg("failed to revoke session upstream")
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		logging.Ctx(ctx).Warn().Int("upstream_status", resp.StatusCode).Str("session_id", sessionID).Msg("failed to revoke session upstream")
+	}
+}
+
+func (u *authUsecase) isSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	if entry, ok := u.sessions.get(sessionID); ok {
+		return entry.revoked, nil
+	}
+
+	session, err := u.repo.FindSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, errorhandler.InternalServerError{Message: err.Error()}
+	}
+
+	return session.RevokedAt != nil, nil
+}
+
+// parseBearerExpired verifies the signature of a "Bearer <token>" value while tolerating expiry.
+func parseBearerExpired(value string) (*token.CustomClaims, error) {
+	rawToken, err := token.ExtractBearer(value)
+	if err != nil {
+		return nil, err
+	}
+	return token.ParseExpiredToken(rawToken)
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/internal/modules/interaction/entity"
+	"github.com/revandpratama/lognest/pkg/patch"
+)
+
+// CreateCommentRequest lists the fields a client may set when commenting. The author is the
+// authenticated user.
+type CreateCommentRequest struct {
+	LogID uuid.UUID `json:"log_id" validate:"required,uuid"`
+	Body  string    `json:"body" validate:"required,min=1,max=255"`
+}
+
+func (r *CreateCommentRequest) ToEntity(userProfileID uuid.UUID) *entity.Comment {
+	return &entity.Comment{
+		UserProfileID: userProfileID,
+		LogID:         r.LogID,
+		Body:          r.Body,
+	}
+}
+
+// UpdateCommentRequest is a JSON Merge Patch of the fields a client may change on a comment.
+type UpdateCommentRequest struct {
+	Body patch.Field[string] `json:"body" validate:"required,min=1,max=255"`
+}
+
+// ToUpdates returns the columns present in the patch, keyed by column name.
+func (r *UpdateCommentRequest) ToUpdates() map[string]any {
+	updates := map[string]any{}
+	if r.Body.Set {
+		updates["body"] = r.Body.Value
+	}
+	return updates
+}
+
+type CommentResponse struct {
+	ID            uuid.UUID `json:"id"`
+	UserProfileID uuid.UUID `json:"user_profile_id"`
+	LogID         uuid.UUID `json:"log_id"`
+	Body          string    `json:"body"`
+	Version       int64     `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func NewCommentResponse(comment *entity.Comment) CommentResponse {
+	return CommentResponse{
+		ID:            comment.ID,
+		UserProfileID: comment.UserProfileID,
+		LogID:         comment.LogID,
+		Body:          comment.Body,
+		Version:       comment.Version,
+		CreatedAt:     comment.CreatedAt,
+		UpdatedAt:     comment.UpdatedAt,
+	}
+}
+
+func NewCommentResponses(comments []entity.Comment) []CommentResponse {
+	res := make([]CommentResponse, 0, len(comments))
+	for i := range comments {
+		res = append(res, NewCommentResponse(&comments[i]))
+	}
+	return res
+}
+
+// CreateLikeRequest lists the fields a client may set when liking a log. The user is the
+// authenticated user.
+type CreateLikeRequest struct {
+	LogID uuid.UUID `json:"log_id" validate:"required,uuid"`
+}
+
+func (r *CreateLikeRequest) ToEntity(userProfileID uuid.UUID) *entity.Like {
+	return &entity.Like{
+		UserProfileID: userProfileID,
+		LogID:         r.LogID,
+	}
+}
+
+type LikeResponse struct {
+	UserProfileID uuid.UUID `json:"user_profile_id"`
+	LogID         uuid.UUID `json:"log_id"`
+}
+
+func NewLikeResponse(like *entity.Like) LikeResponse {
+	return LikeResponse{
+		UserProfileID: like.UserProfileID,
+		LogID:         like.LogID,
+	}
+}
+
+func NewLikeResponses(likes []entity.Like) []LikeResponse {
+	res := make([]LikeResponse, 0, len(likes))
+	for i := range likes {
+		res = append(res, NewLikeResponse(&likes[i]))
+	}
+	return res
+}
@@ -14,10 +14,14 @@ type Comment struct {
 	ID            uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
 	UserProfileID uuid.UUID      `gorm:"type:uuid" json:"user_profile_id"`
 	LogID         uuid.UUID      `gorm:"type:uuid" json:"log_id"`
-	Body          string         `gorm:"type:text;not null" json:"body" validate:"required,min=1,max=255"`
+	Body          string         `gorm:"type:text;not null" json:"body"`
+	Version       int64          `gorm:"not null;default:1" json:"version"`
 	CreatedAt     time.Time      `gorm:"not null" json:"created_at"`
 	UpdatedAt     time.Time      `gorm:"not null" json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// SearchVector indexes Body for search and is generated by Postgres.
+	SearchVector string `gorm:"type:tsvector;index:,type:gin;->:false;<-:false" json:"-"`
 }
 
 // TableName sets the table name for the Interaction.
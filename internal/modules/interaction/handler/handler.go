@@ -6,10 +6,14 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/revandpratama/lognest/internal/modules/interaction/entity"
+	"github.com/revandpratama/lognest/internal/modules/interaction/dto"
 	"github.com/revandpratama/lognest/internal/modules/interaction/usecase"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"github.com/revandpratama/lognest/pkg/httpcache"
+	"github.com/revandpratama/lognest/pkg/pagination"
 	"github.com/revandpratama/lognest/pkg/response"
+	"github.com/revandpratama/lognest/pkg/validation"
 )
 
 // InteractionHandler defines the HTTP handler interface for a Interaction.
@@ -34,25 +38,39 @@ func NewInteractionHandler(usecase usecase.InteractionUsecase) InteractionHandle
 
 func (h *interactionHandler) CreateLike(c *fiber.Ctx) error {
 
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	var newLike entity.Like
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
+	}
+
+	var newLike dto.CreateLikeRequest
 	if err := c.BodyParser(&newLike); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	like, err := h.usecase.CreateLike(ctx, &newLike)
+	if errs := validation.Struct(c, &newLike); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	like, err := h.usecase.CreateLike(ctx, newLike.ToEntity(userID))
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusCreated, "like created", like)
+	return response.Success(c, fiber.StatusCreated, "like created", dto.NewLikeResponse(like))
 }
 
 func (h *interactionHandler) DeleteLike(c *fiber.Ctx) error {
 
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	userIDStr, ok := c.Locals("userID").(string)
@@ -85,7 +103,7 @@ func (h *interactionHandler) DeleteLike(c *fiber.Ctx) error {
 
 func (h *interactionHandler) FindLikeByLogID(c *fiber.Ctx) error {
 
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	logIDStr := c.Params("logID")
@@ -103,30 +121,54 @@ func (h *interactionHandler) FindLikeByLogID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "likes found", likes)
+	res := dto.NewLikeResponses(*likes)
+
+	etag.SetWeak(c, res)
+	if httpcache.NotModified(c, httpcache.Private, time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Success(c, fiber.StatusOK, "likes found", res)
 }
 
 func (h *interactionHandler) CreateComment(c *fiber.Ctx) error {
 
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	var newComment entity.Comment
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
+	}
+
+	var newComment dto.CreateCommentRequest
 	if err := c.BodyParser(&newComment); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	comment, err := h.usecase.CreateComment(ctx, &newComment)
+	if errs := validation.Struct(c, &newComment); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	comment, err := h.usecase.CreateComment(ctx, newComment.ToEntity(userID))
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusCreated, "comment created", comment)
+	res := dto.NewCommentResponse(comment)
+
+	etag.Set(c, comment.Version, res)
+	return response.Success(c, fiber.StatusCreated, "comment created", res)
 }
 
 func (h *interactionHandler) UpdateComment(c *fiber.Ctx) error {
 
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -139,22 +181,34 @@ func (h *interactionHandler) UpdateComment(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	var newComment entity.Comment
-	if err := c.BodyParser(&newComment); err != nil {
+	version, err := etag.IfMatch(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	var updateComment dto.UpdateCommentRequest
+	if err := c.BodyParser(&updateComment); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	comment, err := h.usecase.UpdateComment(ctx, id, &newComment)
+	if errs := validation.Partial(c, &updateComment); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	comment, err := h.usecase.UpdateComment(ctx, id, version, updateComment.ToUpdates())
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "comment updated", comment)
+	res := dto.NewCommentResponse(comment)
+
+	etag.Set(c, comment.Version, res)
+	return response.Success(c, fiber.StatusOK, "comment updated", res)
 }
 
 func (h *interactionHandler) FindCommentByLogID(c *fiber.Ctx) error {
 
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	logIDStr := c.Params("logID")
@@ -167,17 +221,29 @@ func (h *interactionHandler) FindCommentByLogID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid logID format"}, nil)
 	}
 
-	comments, err := h.usecase.FindCommentByLogID(ctx, logID)
+	paginationQuery, err := pagination.Parse(c)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "comments found", comments)
+	comments, pagination, err := h.usecase.FindCommentByLogID(ctx, logID, paginationQuery)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	res := dto.NewCommentResponses(comments)
+
+	etag.SetWeak(c, []any{res, pagination})
+	if httpcache.NotModified(c, httpcache.Private, time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "comments found", res, pagination)
 }
 
 func (h *interactionHandler) DeleteComment(c *fiber.Ctx) error {
 
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	// userIDStr, ok := c.Locals("userID").(string)
@@ -200,7 +266,12 @@ func (h *interactionHandler) DeleteComment(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	err = h.usecase.DeleteComment(ctx, id)
+	version, err := etag.IfMatch(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	err = h.usecase.DeleteComment(ctx, id, version)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/internal/modules/interaction/entity"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"github.com/revandpratama/lognest/pkg/pagination"
 	"gorm.io/gorm"
 )
 
@@ -14,9 +16,9 @@ type InteractionRepository interface {
 	DeleteLike(ctx context.Context, userProfileID uuid.UUID, logID uuid.UUID) error
 	FindLikeByLogID(ctx context.Context, logID uuid.UUID) (*[]entity.Like, error)
 	CreateComment(ctx context.Context, newComment *entity.Comment) (*entity.Comment, error)
-	UpdateComment(ctx context.Context, id uuid.UUID, updateComment *entity.Comment) (*entity.Comment, error)
-	DeleteComment(ctx context.Context, commentID uuid.UUID) error
-	FindCommentByLogID(ctx context.Context, logID uuid.UUID) ([]entity.Comment, error)
+	UpdateComment(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any) (*entity.Comment, error)
+	DeleteComment(ctx context.Context, commentID uuid.UUID, version *int64) error
+	FindCommentByLogID(ctx context.Context, logID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Comment, *pagination.Pagination, error)
 }
 
 type interactionRepository struct {
@@ -50,19 +52,80 @@ func (r *interactionRepository) CreateComment(ctx context.Context, newComment *e
 	return newComment, err
 }
 
-func (r *interactionRepository) UpdateComment(ctx context.Context, id uuid.UUID, updateComment *entity.Comment) (*entity.Comment, error) {
-	err := r.db.WithContext(ctx).Model(&entity.Comment{}).Where("id = ?", id).Updates(updateComment).Error
-	return updateComment, err
+// UpdateComment applies updates to the comment. When version is not nil the update only applies
+// to that version of the comment.
+func (r *interactionRepository) UpdateComment(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any) (*entity.Comment, error) {
+	updates["version"] = gorm.Expr("version + 1")
+
+	query := r.db.WithContext(ctx).Model(&entity.Comment{}).Where("id = ?", id)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, r.missingOrStaleComment(ctx, id)
+	}
+
+	var comment entity.Comment
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&comment).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
 }
 
-func (r *interactionRepository) DeleteComment(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&entity.Comment{}, "id = ?", id).Error
+// DeleteComment removes the comment. When version is not nil it only removes that version of the
+// comment.
+func (r *interactionRepository) DeleteComment(ctx context.Context, id uuid.UUID, version *int64) error {
+	query := r.db.WithContext(ctx).Where("id = ?", id)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+
+	result := query.Delete(&entity.Comment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.missingOrStaleComment(ctx, id)
+	}
+	return nil
 }
 
-func (r *interactionRepository) FindCommentByLogID(ctx context.Context, logID uuid.UUID) ([]entity.Comment, error) {
-	var comments []entity.Comment
-	if err := r.db.WithContext(ctx).Where("log_id = ?", logID).Find(&comments).Error; err != nil {
-		return nil, err
+// missingOrStaleComment explains a conditional write that affected no rows: the comment is either
+// gone or at another version.
+func (r *interactionRepository) missingOrStaleComment(ctx context.Context, id uuid.UUID) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entity.Comment{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return etag.ErrVersionMismatch
+}
+
+func (r *interactionRepository) FindCommentByLogID(ctx context.Context, logID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Comment, *pagination.Pagination, error) {
+	allowedSortColumns := []string{
+		"created_at",
+	}
+
+	allowedFilters := pagination.AllowedFilters{
+		"created_at":      {Column: "created_at", Type: pagination.Time},
+		"user_profile_id": {Column: "user_profile_id", Type: pagination.UUID},
+	}
+
+	query, err := pagination.ApplyFilters(r.db.WithContext(ctx).Where("log_id = ?", logID), paginationQuery, allowedFilters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	comments, err := pagination.FindKeyset[entity.Comment](query, paginationQuery, allowedSortColumns)
+	if err != nil {
+		return nil, nil, err
 	}
-	return comments, nil
+	return comments, paginationQuery, nil
 }
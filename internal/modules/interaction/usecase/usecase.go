@@ -6,6 +6,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/internal/modules/interaction/entity"
 	"github.com/revandpratama/lognest/internal/modules/interaction/repository"
+	"github.com/revandpratama/lognest/pkg/dberror"
+	"github.com/revandpratama/lognest/pkg/pagination"
 )
 
 // InteractionUsecase defines the business logic interface for a Interaction.
@@ -14,9 +16,9 @@ type InteractionUsecase interface {
 	DeleteLike(ctx context.Context, userProfileID uuid.UUID, logID uuid.UUID) error
 	FindLikeByLogID(ctx context.Context, logID uuid.UUID) (*[]entity.Like, error)
 	CreateComment(ctx context.Context, newComment *entity.Comment) (*entity.Comment, error)
-	UpdateComment(ctx context.Context, id uuid.UUID, updateComment *entity.Comment) (*entity.Comment, error)
-	DeleteComment(ctx context.Context, commentID uuid.UUID) error
-	FindCommentByLogID(ctx context.Context, logID uuid.UUID) ([]entity.Comment, error)
+	UpdateComment(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any) (*entity.Comment, error)
+	DeleteComment(ctx context.Context, commentID uuid.UUID, version *int64) error
+	FindCommentByLogID(ctx context.Context, logID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Comment, *pagination.Pagination, error)
 }
 
 type interactionUsecase struct {
@@ -29,29 +31,55 @@ func NewInteractionUsecase(repo repository.InteractionRepository) InteractionUse
 }
 
 func (u *interactionUsecase) CreateLike(ctx context.Context, newLike *entity.Like) (*entity.Like, error) {
-	return u.repo.CreateLike(ctx, newLike)
+	like, err := u.repo.CreateLike(ctx, newLike)
+	if err != nil {
+		return nil, dberror.Translate(err, "like")
+	}
+	return like, nil
 }
 
 func (u *interactionUsecase) DeleteLike(ctx context.Context, userProfileID uuid.UUID, logID uuid.UUID) error {
-	return u.repo.DeleteLike(ctx, userProfileID, logID)
+	if err := u.repo.DeleteLike(ctx, userProfileID, logID); err != nil {
+		return dberror.Translate(err, "like")
+	}
+	return nil
 }
 
 func (u *interactionUsecase) FindLikeByLogID(ctx context.Context, logID uuid.UUID) (*[]entity.Like, error) {
-	return u.repo.FindLikeByLogID(ctx, logID)
+	likes, err := u.repo.FindLikeByLogID(ctx, logID)
+	if err != nil {
+		return nil, dberror.Translate(err, "like")
+	}
+	return likes, nil
 }
 
 func (u *interactionUsecase) CreateComment(ctx context.Context, newComment *entity.Comment) (*entity.Comment, error) {
-	return u.repo.CreateComment(ctx, newComment)
+	comment, err := u.repo.CreateComment(ctx, newComment)
+	if err != nil {
+		return nil, dberror.Translate(err, "comment")
+	}
+	return comment, nil
 }
 
-func (u *interactionUsecase) UpdateComment(ctx context.Context, id uuid.UUID, updateComment *entity.Comment) (*entity.Comment, error) {
-	return u.repo.UpdateComment(ctx, id, updateComment)
+func (u *interactionUsecase) UpdateComment(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any) (*entity.Comment, error) {
+	comment, err := u.repo.UpdateComment(ctx, id, version, updates)
+	if err != nil {
+		return nil, dberror.Translate(err, "comment")
+	}
+	return comment, nil
 }
 
-func (u *interactionUsecase) DeleteComment(ctx context.Context, commentID uuid.UUID) error {
-	return u.repo.DeleteComment(ctx, commentID)
+func (u *interactionUsecase) DeleteComment(ctx context.Context, commentID uuid.UUID, version *int64) error {
+	if err := u.repo.DeleteComment(ctx, commentID, version); err != nil {
+		return dberror.Translate(err, "comment")
+	}
+	return nil
 }
 
-func (u *interactionUsecase) FindCommentByLogID(ctx context.Context, logID uuid.UUID) ([]entity.Comment, error) {
-	return u.repo.FindCommentByLogID(ctx, logID)
+func (u *interactionUsecase) FindCommentByLogID(ctx context.Context, logID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Comment, *pagination.Pagination, error) {
+	comments, pagination, err := u.repo.FindCommentByLogID(ctx, logID, paginationQuery)
+	if err != nil {
+		return nil, nil, dberror.Translate(err, "comment")
+	}
+	return comments, pagination, nil
 }
@@ -0,0 +1,137 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	interactionDto "github.com/revandpratama/lognest/internal/modules/interaction/dto"
+	"github.com/revandpratama/lognest/internal/modules/log/entity"
+	"github.com/revandpratama/lognest/pkg/patch"
+	"github.com/revandpratama/lognest/pkg/validation"
+)
+
+func init() {
+	validation.RegisterPatchFields(patch.Field[[]MediaRequest]{})
+}
+
+// CreateLogRequest lists the fields a client may set when creating a log. The author is the
+// authenticated user and the counters are maintained by the server.
+type CreateLogRequest struct {
+	ProjectID uuid.UUID      `json:"project_id" validate:"required,uuid"`
+	Content   string         `json:"content" validate:"required"`
+	Media     []MediaRequest `json:"media,omitempty" validate:"omitempty,dive"`
+}
+
+func (r *CreateLogRequest) ToEntity(userProfileID uuid.UUID) *entity.Log {
+	return &entity.Log{
+		UserProfileID: userProfileID,
+		ProjectID:     r.ProjectID,
+		Content:       r.Content,
+		Media:         toMediaEntities(r.Media),
+	}
+}
+
+// UpdateLogRequest is a JSON Merge Patch of the fields a client may change on a log.
+type UpdateLogRequest struct {
+	Content patch.Field[string]         `json:"content" validate:"required"`
+	Media   patch.Field[[]MediaRequest] `json:"media" validate:"omitempty,dive"`
+}
+
+// ToUpdates returns the columns present in the patch, keyed by column name.
+func (r *UpdateLogRequest) ToUpdates() map[string]any {
+	updates := map[string]any{}
+	if r.Content.Set {
+		updates["content"] = r.Content.Value
+	}
+	return updates
+}
+
+// ToMedia returns the media replacing the log's media, or nil when the patch leaves them alone.
+// A null or empty list removes all media.
+func (r *UpdateLogRequest) ToMedia() []entity.Media {
+	if !r.Media.Set {
+		return nil
+	}
+	return toMediaEntities(r.Media.Value)
+}
+
+// MediaRequest is a media item attached to a log; it always belongs to the log in the request.
+type MediaRequest struct {
+	FilePath      string `json:"file_path" validate:"required,filepath_prefix,max=255"`
+	ThumbnailPath string `json:"thumbnail_path" validate:"omitempty,filepath_prefix,max=255"`
+	Type          string `json:"type" validate:"required,oneof=image video"`
+	SortOrder     int    `json:"sort_order"`
+}
+
+func toMediaEntities(media []MediaRequest) []entity.Media {
+	res := make([]entity.Media, 0, len(media))
+	for _, m := range media {
+		res = append(res, entity.Media{
+			FilePath:      m.FilePath,
+			ThumbnailPath: m.ThumbnailPath,
+			Type:          m.Type,
+			SortOrder:     m.SortOrder,
+		})
+	}
+	return res
+}
+
+type LogResponse struct {
+	ID            uuid.UUID `json:"id"`
+	UserProfileID uuid.UUID `json:"user_profile_id"`
+	ProjectID     uuid.UUID `json:"project_id"`
+	Content       string    `json:"content"`
+	LikeCount     int       `json:"like_count"`
+	CommentCount  int       `json:"comment_count"`
+	Version       int64     `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	Media    []MediaResponse                  `json:"media,omitempty"`
+	Comments []interactionDto.CommentResponse `json:"comments,omitempty"`
+}
+
+type MediaResponse struct {
+	ID            uuid.UUID `json:"id"`
+	LogID         uuid.UUID `json:"log_id"`
+	FilePath      string    `json:"file_path"`
+	ThumbnailPath string    `json:"thumbnail_path"`
+	Type          string    `json:"type"`
+	SortOrder     int       `json:"sort_order"`
+}
+
+func NewLogResponse(log *entity.Log) LogResponse {
+	res := LogResponse{
+		ID:            log.ID,
+		UserProfileID: log.UserProfileID,
+		ProjectID:     log.ProjectID,
+		Content:       log.Content,
+		LikeCount:     log.LikeCount,
+		CommentCount:  log.CommentCount,
+		Version:       log.Version,
+		CreatedAt:     log.CreatedAt,
+		UpdatedAt:     log.UpdatedAt,
+		Comments:      interactionDto.NewCommentResponses(log.Comments),
+	}
+
+	for _, media := range log.Media {
+		res.Media = append(res.Media, MediaResponse{
+			ID:            media.ID,
+			LogID:         media.LogID,
+			FilePath:      media.FilePath,
+			ThumbnailPath: media.ThumbnailPath,
+			Type:          media.Type,
+			SortOrder:     media.SortOrder,
+		})
+	}
+
+	return res
+}
+
+func NewLogResponses(logs []entity.Log) []LogResponse {
+	res := make([]LogResponse, 0, len(logs))
+	for i := range logs {
+		res = append(res, NewLogResponse(&logs[i]))
+	}
+	return res
+}
@@ -13,15 +13,19 @@ import (
 // Log represents the data structure for a log.
 type Log struct {
 	ID            uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
-	UserProfileID uuid.UUID      `gorm:"not null" json:"user_profile_id"`
+	UserProfileID uuid.UUID      `gorm:"type:uuid;not null" json:"user_profile_id"`
 	ProjectID     uuid.UUID      `gorm:"not null" json:"project_id"`
-	Content       string         `gorm:"type:text;not null" json:"content" validate:"required"`
+	Content       string         `gorm:"type:text;not null" json:"content"`
 	LikeCount     int            `gorm:"default:0" json:"like_count"`
 	CommentCount  int            `gorm:"default:0" json:"comment_count"`
+	Version       int64          `gorm:"not null;default:1" json:"version"`
 	CreatedAt     time.Time      `gorm:"not null" json:"created_at"`
 	UpdatedAt     time.Time      `gorm:"not null" json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// SearchVector indexes Content for search and is generated by Postgres.
+	SearchVector string `gorm:"type:tsvector;index:,type:gin;->:false;<-:false" json:"-"`
+
 	Media    []Media                     `gorm:"foreignKey:LogID;references:ID;constraint:OnDelete:CASCADE;" json:"media,omitempty"`
 	Comments []interactionEntity.Comment `gorm:"foreignKey:LogID;references:ID;constraint:OnDelete:CASCADE;" json:"comments,omitempty"`
 }
@@ -31,7 +35,7 @@ type Media struct {
 	LogID         uuid.UUID `gorm:"not null" json:"log_id"`
 	FilePath      string    `gorm:"type:varchar(255);not null" json:"file_path"`
 	ThumbnailPath string    `gorm:"type:varchar(255);not null" json:"thumbnail_path"`
-	Type          string    `gorm:"type:enum('image', 'video');not null" json:"type"`
+	Type          string    `gorm:"type:varchar(20);not null" json:"type"`
 	SortOrder     int       `gorm:"default:0" json:"sort_order"`
 }
 
@@ -50,3 +54,19 @@ func (p *Log) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// TableName sets the table name for the Media.
+func (Media) TableName() string {
+	return fmt.Sprintf("%s.%s", config.ENV.LOGNEST_SCHEMA, "media")
+}
+
+func (m *Media) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		uuidGenerated, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		m.ID = uuidGenerated
+	}
+	return nil
+}
@@ -6,11 +6,14 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/revandpratama/lognest/internal/modules/log/entity"
+	"github.com/revandpratama/lognest/internal/modules/log/dto"
 	"github.com/revandpratama/lognest/internal/modules/log/usecase"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"github.com/revandpratama/lognest/pkg/httpcache"
 	"github.com/revandpratama/lognest/pkg/pagination"
 	"github.com/revandpratama/lognest/pkg/response"
+	"github.com/revandpratama/lognest/pkg/validation"
 )
 
 // LogHandler defines the HTTP handler interface for a Log.
@@ -32,7 +35,7 @@ func NewLogHandler(usecase usecase.LogUsecase) LogHandler {
 }
 
 func (h *logHandler) FindByID(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -50,11 +53,23 @@ func (h *logHandler) FindByID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "log found", log)
+	isPublic, err := h.usecase.IsProjectPublic(ctx, log.ProjectID)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	res := dto.NewLogResponse(log)
+
+	etag.Set(c, log.Version, res)
+	if httpcache.NotModified(c, httpcache.For(isPublic), log.UpdatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Success(c, fiber.StatusOK, "log found", res)
 }
 
 func (h *logHandler) FindByProjectID(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	projectIDStr := c.Params("projectID")
@@ -67,9 +82,9 @@ func (h *logHandler) FindByProjectID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	paginationQuery := new(pagination.Pagination)
-	if err := c.QueryParser(paginationQuery); err != nil {
-		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
+	paginationQuery, err := pagination.Parse(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
 	}
 
 	logs, pagination, err := h.usecase.FindByProjectID(ctx, projectID, paginationQuery)
@@ -77,28 +92,57 @@ func (h *logHandler) FindByProjectID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Paginated(c, fiber.StatusOK, "logs found", logs, pagination)
+	isPublic, err := h.usecase.IsProjectPublic(ctx, projectID)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	res := dto.NewLogResponses(logs)
+
+	etag.SetWeak(c, []any{res, pagination})
+	if httpcache.NotModified(c, httpcache.For(isPublic), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "logs found", res, pagination)
 }
 
 func (h *logHandler) Create(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	var newLog entity.Log
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
+	}
+
+	var newLog dto.CreateLogRequest
 	if err := c.BodyParser(&newLog); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	log, err := h.usecase.Create(ctx, &newLog)
+	if errs := validation.Struct(c, &newLog); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	log, err := h.usecase.Create(ctx, newLog.ToEntity(userID))
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusCreated, "log created", log)
+	res := dto.NewLogResponse(log)
+
+	etag.Set(c, log.Version, res)
+	return response.Success(c, fiber.StatusCreated, "log created", res)
 }
 
 func (h *logHandler) Update(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -111,21 +155,33 @@ func (h *logHandler) Update(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	var updateLog entity.Log
+	version, err := etag.IfMatch(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	var updateLog dto.UpdateLogRequest
 	if err := c.BodyParser(&updateLog); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	log, err := h.usecase.Update(ctx, id, &updateLog)
+	if errs := validation.Partial(c, &updateLog); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	log, err := h.usecase.Update(ctx, id, version, updateLog.ToUpdates(), updateLog.ToMedia())
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "log updated", log)
+	res := dto.NewLogResponse(log)
+
+	etag.Set(c, log.Version, res)
+	return response.Success(c, fiber.StatusOK, "log updated", res)
 }
 
 func (h *logHandler) Delete(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -138,7 +194,12 @@ func (h *logHandler) Delete(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	err = h.usecase.Delete(ctx, id)
+	version, err := etag.IfMatch(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	err = h.usecase.Delete(ctx, id, version)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
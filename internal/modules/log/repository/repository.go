@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/config"
 	"github.com/revandpratama/lognest/internal/modules/log/entity"
+	"github.com/revandpratama/lognest/pkg/etag"
 	"github.com/revandpratama/lognest/pkg/pagination"
 	"gorm.io/gorm"
 )
@@ -14,8 +18,9 @@ type LogRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Log, error)
 	FindByProjectID(ctx context.Context, projectID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Log, *pagination.Pagination, error)
 	Create(ctx context.Context, newLog *entity.Log) (*entity.Log, error)
-	Update(ctx context.Context, id uuid.UUID, updateLog *entity.Log) (*entity.Log, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any, media []entity.Media) (*entity.Log, error)
+	Delete(ctx context.Context, id uuid.UUID, version *int64) error
+	IsProjectPublic(ctx context.Context, projectID uuid.UUID) (bool, error)
 }
 
 type logRepository struct {
@@ -37,20 +42,44 @@ func (r *logRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Log
 	return &log, nil
 }
 
-func (r *logRepository) FindByProjectID(ctx context.Context, projectID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Log, *pagination.Pagination, error) {
-	var logs []entity.Log
+// IsProjectPublic reports whether the project a log belongs to is public. A project without
+// is_public is public, as everywhere else; one that is missing or deleted is not.
+func (r *logRepository) IsProjectPublic(ctx context.Context, projectID uuid.UUID) (bool, error) {
+	var project struct{ IsPublic *bool }
+	err := r.db.WithContext(ctx).
+		Table(fmt.Sprintf("%s.%s", config.ENV.LOGNEST_SCHEMA, "projects")).
+		Select("is_public").
+		Where("id = ? AND deleted_at IS NULL", projectID).
+		Take(&project).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return project.IsPublic == nil || *project.IsPublic, nil
+}
 
+func (r *logRepository) FindByProjectID(ctx context.Context, projectID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Log, *pagination.Pagination, error) {
 	allowedSortColumns := []string{
 		"created_at",
-		"title",
-		"is_public",
+		"like_count",
+		"comment_count",
 	}
 
-	query := r.db.WithContext(ctx).Where("project_id = ?", projectID)
+	allowedFilters := pagination.AllowedFilters{
+		"created_at":    {Column: "created_at", Type: pagination.Time},
+		"like_count":    {Column: "like_count", Type: pagination.Int},
+		"comment_count": {Column: "comment_count", Type: pagination.Int},
+	}
 
-	paginatedDB := pagination.Paginate(query, paginationQuery, &logs, allowedSortColumns)
+	query, err := pagination.ApplyFilters(r.db.WithContext(ctx).Where("project_id = ?", projectID), paginationQuery, allowedFilters)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if err := paginatedDB.Preload("Comments").Preload("Media").Find(&logs).Error; err != nil {
+	logs, err := pagination.FindKeyset[entity.Log](query, paginationQuery, allowedSortColumns, "Comments", "Media")
+	if err != nil {
 		return nil, nil, err
 	}
 	return logs, paginationQuery, nil
@@ -61,11 +90,76 @@ func (r *logRepository) Create(ctx context.Context, newLog *entity.Log) (*entity
 	return newLog, err
 }
 
-func (r *logRepository) Update(ctx context.Context, id uuid.UUID, updateLog *entity.Log) (*entity.Log, error) {
-	err := r.db.WithContext(ctx).Model(&entity.Log{}).Where("id = ?", id).Updates(updateLog).Error
-	return updateLog, err
+// Update applies updates to the log and, when media is not nil, replaces its media. When version is
+// not nil the update only applies to that version of the log.
+func (r *logRepository) Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any, media []entity.Media) (*entity.Log, error) {
+	updates["version"] = gorm.Expr("version + 1")
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&entity.Log{}).Where("id = ?", id)
+		if version != nil {
+			query = query.Where("version = ?", *version)
+		}
+
+		// updated_at is always set, so no affected rows means the log does not exist or has moved
+		// past version.
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return r.missingOrStale(tx, id)
+		}
+
+		if media == nil {
+			return nil
+		}
+		if err := tx.Where("log_id = ?", id).Delete(&entity.Media{}).Error; err != nil {
+			return err
+		}
+		if len(media) == 0 {
+			return nil
+		}
+		for i := range media {
+			media[i].LogID = id
+		}
+		return tx.Create(&media).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
 }
 
-func (r *logRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&entity.Log{}, "id = ?", id).Error
+// Delete removes the log. When version is not nil it only removes that version of the log.
+func (r *logRepository) Delete(ctx context.Context, id uuid.UUID, version *int64) error {
+	db := r.db.WithContext(ctx)
+
+	query := db.Where("id = ?", id)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+
+	result := query.Delete(&entity.Log{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.missingOrStale(db, id)
+	}
+	return nil
+}
+
+// missingOrStale explains a conditional write that affected no rows: the log is either gone or at
+// another version.
+func (r *logRepository) missingOrStale(db *gorm.DB, id uuid.UUID) error {
+	var count int64
+	if err := db.Model(&entity.Log{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return etag.ErrVersionMismatch
 }
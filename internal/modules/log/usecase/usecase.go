@@ -2,13 +2,12 @@ package usecase
 
 import (
 	"context"
-	"errors"
 
 	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/internal/modules/log/entity"
 	"github.com/revandpratama/lognest/internal/modules/log/repository"
+	"github.com/revandpratama/lognest/pkg/dberror"
 	"github.com/revandpratama/lognest/pkg/pagination"
-	"gorm.io/gorm"
 )
 
 // LogUsecase defines the business logic interface for a Log.
@@ -16,8 +15,9 @@ type LogUsecase interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Log, error)
 	FindByProjectID(ctx context.Context, projectID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Log, *pagination.Pagination, error)
 	Create(ctx context.Context, newLog *entity.Log) (*entity.Log, error)
-	Update(ctx context.Context, id uuid.UUID, updateLog *entity.Log) (*entity.Log, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any, media []entity.Media) (*entity.Log, error)
+	Delete(ctx context.Context, id uuid.UUID, version *int64) error
+	IsProjectPublic(ctx context.Context, projectID uuid.UUID) (bool, error)
 }
 
 type logUsecase struct {
@@ -32,27 +32,47 @@ func NewLogUsecase(repo repository.LogRepository) LogUsecase {
 func (u *logUsecase) FindByID(ctx context.Context, id uuid.UUID) (*entity.Log, error) {
 	log, err := u.repo.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("log not found")
-		}
-		return nil, err
+		return nil, dberror.Translate(err, "log")
 	}
 
 	return log, nil
 }
 
 func (u *logUsecase) FindByProjectID(ctx context.Context, projectID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Log, *pagination.Pagination, error) {
-	return u.repo.FindByProjectID(ctx, projectID, paginationQuery)
+	logs, pagination, err := u.repo.FindByProjectID(ctx, projectID, paginationQuery)
+	if err != nil {
+		return nil, nil, dberror.Translate(err, "log")
+	}
+	return logs, pagination, nil
 }
 
 func (u *logUsecase) Create(ctx context.Context, newLog *entity.Log) (*entity.Log, error) {
-	return u.repo.Create(ctx, newLog)
+	log, err := u.repo.Create(ctx, newLog)
+	if err != nil {
+		return nil, dberror.Translate(err, "log")
+	}
+	return log, nil
 }
 
-func (u *logUsecase) Update(ctx context.Context, id uuid.UUID, updateLog *entity.Log) (*entity.Log, error) {
-	return u.repo.Update(ctx, id, updateLog)
+func (u *logUsecase) Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any, media []entity.Media) (*entity.Log, error) {
+	log, err := u.repo.Update(ctx, id, version, updates, media)
+	if err != nil {
+		return nil, dberror.Translate(err, "log")
+	}
+	return log, nil
+}
+
+func (u *logUsecase) Delete(ctx context.Context, id uuid.UUID, version *int64) error {
+	if err := u.repo.Delete(ctx, id, version); err != nil {
+		return dberror.Translate(err, "log")
+	}
+	return nil
 }
 
-func (u *logUsecase) Delete(ctx context.Context, id uuid.UUID) error {
-	return u.repo.Delete(ctx, id)
+func (u *logUsecase) IsProjectPublic(ctx context.Context, projectID uuid.UUID) (bool, error) {
+	isPublic, err := u.repo.IsProjectPublic(ctx, projectID)
+	if err != nil {
+		return false, dberror.Translate(err, "project")
+	}
+	return isPublic, nil
 }
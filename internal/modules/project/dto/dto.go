@@ -0,0 +1,144 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	logDto "github.com/revandpratama/lognest/internal/modules/log/dto"
+	"github.com/revandpratama/lognest/internal/modules/project/entity"
+	tagDto "github.com/revandpratama/lognest/internal/modules/tag/dto"
+	tagEntity "github.com/revandpratama/lognest/internal/modules/tag/entity"
+	userProfileDto "github.com/revandpratama/lognest/internal/modules/user-profile/dto"
+	"github.com/revandpratama/lognest/pkg/patch"
+	"github.com/revandpratama/lognest/pkg/validation"
+)
+
+func init() {
+	validation.RegisterPatchFields(patch.Field[[]TagReference]{})
+}
+
+// CreateProjectRequest lists the fields a client may set when creating a project. The owner is
+// the authenticated user and the slug is derived from the title.
+type CreateProjectRequest struct {
+	Title          string         `json:"title" validate:"required,min=5,max=255"`
+	Description    string         `json:"description"`
+	CoverImagePath string         `json:"cover_image_path" validate:"omitempty,filepath_prefix,max=255"`
+	IsPublic       *bool          `json:"is_public"`
+	Tags           []TagReference `json:"tags,omitempty" validate:"omitempty,dive"`
+}
+
+func (r *CreateProjectRequest) ToEntity(userProfileID uuid.UUID) *entity.Project {
+	return &entity.Project{
+		UserProfileID:  userProfileID,
+		Title:          r.Title,
+		Description:    r.Description,
+		CoverImagePath: r.CoverImagePath,
+		IsPublic:       r.IsPublic,
+		Tags:           toTagEntities(r.Tags),
+	}
+}
+
+// UpdateProjectRequest is a JSON Merge Patch of the fields a client may change on a project.
+// Title and slug cannot be removed; tags, when present, replace the project's tags.
+type UpdateProjectRequest struct {
+	Title          patch.Field[string]         `json:"title" validate:"required,min=5,max=255"`
+	Description    patch.Field[string]         `json:"description"`
+	Slug           patch.Field[string]         `json:"slug" validate:"required,slug,max=255"`
+	CoverImagePath patch.Field[string]         `json:"cover_image_path" validate:"omitempty,filepath_prefix,max=255"`
+	IsPublic       patch.Field[bool]           `json:"is_public"`
+	Tags           patch.Field[[]TagReference] `json:"tags" validate:"omitempty,dive"`
+}
+
+// ToUpdates returns the columns present in the patch, keyed by column name. Null clears the
+// description and cover image and restores is_public to its default.
+func (r *UpdateProjectRequest) ToUpdates() map[string]any {
+	updates := map[string]any{}
+	if r.Title.Set {
+		updates["title"] = r.Title.Value
+	}
+	if r.Description.Set {
+		updates["description"] = r.Description.Value
+	}
+	if r.Slug.Set {
+		updates["slug"] = r.Slug.Value
+	}
+	if r.CoverImagePath.Set {
+		updates["cover_image_path"] = r.CoverImagePath.Value
+	}
+	if r.IsPublic.Set {
+		updates["is_public"] = r.IsPublic.Null || r.IsPublic.Value
+	}
+	return updates
+}
+
+// ToTags returns the tags replacing the project's tags, or nil when the patch leaves them alone.
+// A null or empty list removes all tags.
+func (r *UpdateProjectRequest) ToTags() []tagEntity.Tag {
+	if !r.Tags.Set {
+		return nil
+	}
+	return toTagEntities(r.Tags.Value)
+}
+
+// TagReference links an existing tag to a project; tags are managed through the tag module.
+type TagReference struct {
+	ID uuid.UUID `json:"id" validate:"required,uuid"`
+}
+
+func toTagEntities(tags []TagReference) []tagEntity.Tag {
+	res := make([]tagEntity.Tag, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, tagEntity.Tag{ID: tag.ID})
+	}
+	return res
+}
+
+type ProjectResponse struct {
+	ID             uuid.UUID `json:"id"`
+	UserProfileID  uuid.UUID `json:"user_profile_id"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Slug           string    `json:"slug"`
+	CoverImagePath string    `json:"cover_image_path"`
+	IsPublic       *bool     `json:"is_public"`
+	Version        int64     `json:"version"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	UserProfile *userProfileDto.UserProfileResponse `json:"user_profile,omitempty"`
+	Logs        []logDto.LogResponse                `json:"logs,omitempty"`
+	Tags        []tagDto.TagResponse                `json:"tags,omitempty"`
+}
+
+func NewProjectResponse(project *entity.Project) ProjectResponse {
+	res := ProjectResponse{
+		ID:             project.ID,
+		UserProfileID:  project.UserProfileID,
+		Title:          project.Title,
+		Description:    project.Description,
+		Slug:           project.Slug,
+		CoverImagePath: project.CoverImagePath,
+		IsPublic:       project.IsPublic,
+		Version:        project.Version,
+		CreatedAt:      project.CreatedAt,
+		UpdatedAt:      project.UpdatedAt,
+		Logs:           logDto.NewLogResponses(project.Logs),
+		Tags:           tagDto.NewTagResponses(project.Tags),
+	}
+
+	// The owner is only set when the query preloaded it.
+	if project.UserProfile.UserID != uuid.Nil {
+		userProfile := userProfileDto.NewUserProfileResponse(&project.UserProfile)
+		res.UserProfile = &userProfile
+	}
+
+	return res
+}
+
+func NewProjectResponses(projects []entity.Project) []ProjectResponse {
+	res := make([]ProjectResponse, 0, len(projects))
+	for i := range projects {
+		res = append(res, NewProjectResponse(&projects[i]))
+	}
+	return res
+}
@@ -15,15 +15,20 @@ import (
 type Project struct {
 	ID             uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
 	UserProfileID  uuid.UUID      `gorm:"not null" json:"user_profile_id"`
-	Title          string         `gorm:"type:varchar(255);not null" json:"title" validate:"required,min=5,max=255"`
+	Title          string         `gorm:"type:varchar(255);not null" json:"title"`
 	Description    string         `gorm:"type:text" json:"description"`
 	Slug           string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
 	CoverImagePath string         `gorm:"type:varchar(255)" json:"cover_image_path"`
 	IsPublic       *bool          `gorm:"default:true" json:"is_public"`
+	Version        int64          `gorm:"not null;default:1" json:"version"`
 	CreatedAt      time.Time      `gorm:"not null" json:"created_at"`
 	UpdatedAt      time.Time      `gorm:"not null" json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// SearchVector is the full-text search document of the title and description. Postgres
+	// generates it, so GORM never reads or writes it.
+	SearchVector string `gorm:"type:tsvector;index:,type:gin;->:false;<-:false" json:"-"`
+
 	// --- Relationships ---
 	UserProfile userProfileEntity.UserProfile `gorm:"foreignKey:UserProfileID;references:UserID" json:"user_profile"`
 	Logs        []logEntity.Log               `gorm:"foreignKey:ProjectID;references:ID;constraint:OnDelete:CASCADE;" json:"logs,omitempty"` // CASCADE means if project is deleted, its logs are too
@@ -6,11 +6,14 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/revandpratama/lognest/internal/modules/project/entity"
-	"github.com/revandpratama/lognest/internal/modules/project/repository"
+	"github.com/revandpratama/lognest/internal/modules/project/dto"
+	"github.com/revandpratama/lognest/internal/modules/project/usecase"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"github.com/revandpratama/lognest/pkg/httpcache"
 	"github.com/revandpratama/lognest/pkg/pagination"
 	"github.com/revandpratama/lognest/pkg/response"
+	"github.com/revandpratama/lognest/pkg/validation"
 )
 
 type ProjectHandler interface {
@@ -25,15 +28,15 @@ type ProjectHandler interface {
 }
 
 type projectHandler struct {
-	ProjectRepository repository.ProjectRepository
+	ProjectUsecase usecase.ProjectUsecase
 }
 
-func NewProjectHandler(projectRepository repository.ProjectRepository) ProjectHandler {
-	return &projectHandler{ProjectRepository: projectRepository}
+func NewProjectHandler(projectUsecase usecase.ProjectUsecase) ProjectHandler {
+	return &projectHandler{ProjectUsecase: projectUsecase}
 }
 
 func (h *projectHandler) FindBySlug(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	slug := c.Params("slug")
@@ -41,38 +44,63 @@ func (h *projectHandler) FindBySlug(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "slug is required"}, nil)
 	}
 
-	project, err := h.ProjectRepository.FindBySlug(ctx, slug)
+	project, err := h.ProjectUsecase.FindBySlug(ctx, slug)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "project found", project)
+	res := dto.NewProjectResponse(project)
+
+	// The owner's profile is part of the response, so it can make the response newer.
+	lastModified := project.UpdatedAt
+	if project.UserProfile.UpdatedAt.After(lastModified) {
+		lastModified = project.UserProfile.UpdatedAt
+	}
+
+	etag.Set(c, project.Version, res)
+	if httpcache.NotModified(c, cachePolicy(res), lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Success(c, fiber.StatusOK, "project found", res)
 }
 
 func (h *projectHandler) FindByUserID(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	userID, ok := c.Locals("user_id").(uuid.UUID)
+	userIDStr, ok := c.Locals("userID").(string)
 	if !ok {
-		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: user_id not found"}, nil)
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
 	}
 
-	paginationQuery := new(pagination.Pagination)
-	if err := c.QueryParser(paginationQuery); err != nil {
-		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
 	}
 
-	projects, pagination, err := h.ProjectRepository.FindByUserID(ctx, userID, paginationQuery)
+	paginationQuery, err := pagination.Parse(c)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Paginated(c, fiber.StatusOK, "projects found", projects, pagination)
+	projects, pagination, err := h.ProjectUsecase.FindByUserID(ctx, userID, paginationQuery)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	res := dto.NewProjectResponses(projects)
+
+	etag.SetWeak(c, []any{res, pagination})
+	if httpcache.NotModified(c, httpcache.Private, time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "projects found", res, pagination)
 }
 
 func (h *projectHandler) FindByPublicUserID(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	userIDStr := c.Params("userID")
@@ -85,21 +113,28 @@ func (h *projectHandler) FindByPublicUserID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	paginationQuery := new(pagination.Pagination)
-	if err := c.QueryParser(paginationQuery); err != nil {
-		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
+	paginationQuery, err := pagination.Parse(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
 	}
 
-	projects, pagination, err := h.ProjectRepository.FindByUserID(ctx, userID, paginationQuery)
+	projects, pagination, err := h.ProjectUsecase.FindByUserID(ctx, userID, paginationQuery)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Paginated(c, fiber.StatusOK, "projects found", projects, pagination)
+	res := dto.NewProjectResponses(projects)
+
+	etag.SetWeak(c, []any{res, pagination})
+	if httpcache.NotModified(c, cachePolicy(res...), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "projects found", res, pagination)
 }
 
 func (h *projectHandler) FindByID(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -112,51 +147,84 @@ func (h *projectHandler) FindByID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	project, err := h.ProjectRepository.FindByID(ctx, id)
+	project, err := h.ProjectUsecase.FindByID(ctx, id)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "project found", project)
+	res := dto.NewProjectResponse(project)
+
+	// Logs and tags added, removed or renamed since UpdatedAt change the response too, so only the
+	// ETag can tell whether it is current.
+	etag.Set(c, project.Version, res)
+	if httpcache.NotModified(c, cachePolicy(res), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Success(c, fiber.StatusOK, "project found", res)
 }
 
 func (h *projectHandler) FindAll(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	paginationQuery := new(pagination.Pagination)
-	if err := c.QueryParser(paginationQuery); err != nil {
-		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
+	paginationQuery, err := pagination.Parse(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
 	}
 
-	projects, pagination, err := h.ProjectRepository.FindAll(ctx, paginationQuery)
+	projects, pagination, err := h.ProjectUsecase.FindAll(ctx, paginationQuery)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Paginated(c, fiber.StatusOK, "projects found", projects, pagination)
+	res := dto.NewProjectResponses(projects)
+
+	etag.SetWeak(c, []any{res, pagination})
+	if httpcache.NotModified(c, cachePolicy(res...), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "projects found", res, pagination)
 }
 
 func (h *projectHandler) Create(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	var newProject entity.Project
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
+	}
+
+	var newProject dto.CreateProjectRequest
 
 	if err := c.BodyParser(&newProject); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	project, err := h.ProjectRepository.Create(ctx, &newProject)
+	if errs := validation.Struct(c, &newProject); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	project, err := h.ProjectUsecase.Create(ctx, newProject.ToEntity(userID))
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusCreated, "project created", project)
+	res := dto.NewProjectResponse(project)
+
+	etag.Set(c, project.Version, res)
+	return response.Success(c, fiber.StatusCreated, "project created", res)
 
 }
 func (h *projectHandler) Update(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -169,22 +237,34 @@ func (h *projectHandler) Update(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	var updateProject entity.Project
+	version, err := etag.IfMatch(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	var updateProject dto.UpdateProjectRequest
 
 	if err := c.BodyParser(&updateProject); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	project, err := h.ProjectRepository.Update(ctx, id, &updateProject)
+	if errs := validation.Partial(c, &updateProject); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	project, err := h.ProjectUsecase.Update(ctx, id, version, updateProject.ToUpdates(), updateProject.ToTags())
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "project updated", project)
+	res := dto.NewProjectResponse(project)
+
+	etag.Set(c, project.Version, res)
+	return response.Success(c, fiber.StatusOK, "project updated", res)
 }
 
 func (h *projectHandler) Delete(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -197,10 +277,25 @@ func (h *projectHandler) Delete(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	err = h.ProjectRepository.Delete(ctx, id)
+	version, err := etag.IfMatch(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	err = h.ProjectUsecase.Delete(ctx, id, version)
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
 	return response.Success(c, fiber.StatusOK, "project deleted", nil)
 }
+
+// cachePolicy lets shared caches store a response only when every project in it is public.
+// httpcache.NotModified still narrows it for signed-in clients and responses that set cookies.
+func cachePolicy(projects ...dto.ProjectResponse) httpcache.Policy {
+	isPublic := true
+	for _, project := range projects {
+		isPublic = isPublic && (project.IsPublic == nil || *project.IsPublic)
+	}
+	return httpcache.For(isPublic)
+}
@@ -2,21 +2,28 @@ package repository
 
 import (
 	"context"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/config"
 	"github.com/revandpratama/lognest/internal/modules/project/entity"
+	tagEntity "github.com/revandpratama/lognest/internal/modules/tag/entity"
+	"github.com/revandpratama/lognest/pkg/etag"
 	"github.com/revandpratama/lognest/pkg/pagination"
 	"gorm.io/gorm"
 )
 
+// tagSubquery matches projects carrying a live tag; ApplyFilters fills in the condition on tags.name.
+const tagSubquery = "id IN (SELECT project_tags.project_id FROM {{schema}}.project_tags JOIN {{schema}}.tags ON tags.id = project_tags.tag_id WHERE tags.deleted_at IS NULL AND %s)"
+
 type ProjectRepository interface {
 	FindBySlug(ctx context.Context, slug string) (*entity.Project, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Project, *pagination.Pagination, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Project, error)
 	FindAll(ctx context.Context, paginationQuery *pagination.Pagination) ([]entity.Project, *pagination.Pagination, error)
 	Create(ctx context.Context, newProject *entity.Project) (*entity.Project, error)
-	Update(ctx context.Context, id uuid.UUID, updateProject *entity.Project) (*entity.Project, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any, tags []tagEntity.Tag) (*entity.Project, error)
+	Delete(ctx context.Context, id uuid.UUID, version *int64) error
 }
 
 type projectRepository struct {
@@ -48,11 +55,26 @@ func (r *projectRepository) FindByUserID(ctx context.Context, userID uuid.UUID,
 
 	allowedSortColumns := []string{
 		"created_at",
+		"updated_at",
 		"title",
 		"is_public",
 	}
 
-	query := r.db.WithContext(ctx).Preload("UserProfile").Where("user_id = ?", userID)
+	allowedFilters := pagination.AllowedFilters{
+		"tag": {
+			Column:   "tags.name",
+			Subquery: strings.ReplaceAll(tagSubquery, "{{schema}}", config.ENV.LOGNEST_SCHEMA),
+		},
+		"title":      {Column: "title"},
+		"is_public":  {Column: "is_public", Type: pagination.Bool},
+		"created_at": {Column: "created_at", Type: pagination.Time},
+		"updated_at": {Column: "updated_at", Type: pagination.Time},
+	}
+
+	query, err := pagination.ApplyFilters(r.db.WithContext(ctx).Preload("UserProfile").Where("user_profile_id = ?", userID), paginationQuery, allowedFilters)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	paginatedDB := pagination.Paginate(query, paginationQuery, &projects, allowedSortColumns)
 
@@ -67,11 +89,28 @@ func (r *projectRepository) FindAll(ctx context.Context, paginationQuery *pagina
 
 	allowedSortColumns := []string{
 		"created_at",
+		"updated_at",
 		"title",
 		"is_public",
 	}
 
-	paginatedDB := pagination.Paginate(r.db, paginationQuery, &projects, allowedSortColumns)
+	allowedFilters := pagination.AllowedFilters{
+		"tag": {
+			Column:   "tags.name",
+			Subquery: strings.ReplaceAll(tagSubquery, "{{schema}}", config.ENV.LOGNEST_SCHEMA),
+		},
+		"title":      {Column: "title"},
+		"is_public":  {Column: "is_public", Type: pagination.Bool},
+		"created_at": {Column: "created_at", Type: pagination.Time},
+		"updated_at": {Column: "updated_at", Type: pagination.Time},
+	}
+
+	query, err := pagination.ApplyFilters(r.db.WithContext(ctx), paginationQuery, allowedFilters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paginatedDB := pagination.Paginate(query, paginationQuery, &projects, allowedSortColumns)
 
 	if err := paginatedDB.Preload("Tags").Preload("UserProfile").Find(&projects).Error; err != nil {
 		return nil, nil, err
@@ -104,35 +143,79 @@ func (r *projectRepository) Create(ctx context.Context, newProject *entity.Proje
 	return newProject, err
 }
 
-func (r *projectRepository) Update(ctx context.Context, id uuid.UUID, updateProject *entity.Project) (*entity.Project, error) {
+// Update applies updates to the project and, when tags is not nil, replaces its tags. When version
+// is not nil the update only applies to that version of the project.
+func (r *projectRepository) Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any, tags []tagEntity.Tag) (*entity.Project, error) {
 
-	err := r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.WithContext(ctx).Model(&entity.Project{}).Where("id = ?", id).Updates(updateProject).Error; err != nil {
-			return err
+	updates["version"] = gorm.Expr("version + 1")
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&entity.Project{}).Where("id = ?", id)
+		if version != nil {
+			query = query.Where("version = ?", *version)
+		}
+
+		// updated_at is always set, so no affected rows means the project does not exist or has
+		// moved past version.
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return r.missingOrStale(tx, id)
 		}
 
-		if len(updateProject.Tags) > 0 {
+		if tags != nil {
 			if err := tx.Exec("DELETE FROM lognest.project_tags WHERE project_id = ?", id).Error; err != nil {
 				return err
 			}
-			if len(updateProject.Tags) > 0 {
-				for _, tag := range updateProject.Tags {
-					joinRecord := map[string]interface{}{
-						"project_id": id,
-						"tag_id":     tag.ID,
-					}
-					if err := tx.Table("lognest.project_tags").Create(&joinRecord).Error; err != nil {
-						return err
-					}
+			for _, tag := range tags {
+				joinRecord := map[string]interface{}{
+					"project_id": id,
+					"tag_id":     tag.ID,
+				}
+				if err := tx.Table("lognest.project_tags").Create(&joinRecord).Error; err != nil {
+					return err
 				}
 			}
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// Delete removes the project. When version is not nil it only removes that version of the project.
+func (r *projectRepository) Delete(ctx context.Context, id uuid.UUID, version *int64) error {
+	db := r.db.WithContext(ctx)
+
+	query := db.Where("id = ?", id)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
 
-	return updateProject, err
+	result := query.Delete(&entity.Project{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.missingOrStale(db, id)
+	}
+	return nil
 }
 
-func (r *projectRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&entity.Project{}, "id = ?", id).Error
+// missingOrStale explains a conditional write that affected no rows: the project is either gone
+// or at another version.
+func (r *projectRepository) missingOrStale(db *gorm.DB, id uuid.UUID) error {
+	var count int64
+	if err := db.Model(&entity.Project{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return etag.ErrVersionMismatch
 }
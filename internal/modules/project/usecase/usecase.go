@@ -2,15 +2,14 @@ package usecase
 
 import (
 	"context"
-	"errors"
 
 	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/internal/modules/project/entity"
 	"github.com/revandpratama/lognest/internal/modules/project/repository"
-	"github.com/revandpratama/lognest/pkg/errorhandler"
+	tagEntity "github.com/revandpratama/lognest/internal/modules/tag/entity"
+	"github.com/revandpratama/lognest/pkg/dberror"
 	"github.com/revandpratama/lognest/pkg/pagination"
 	"github.com/revandpratama/lognest/pkg/slug"
-	"gorm.io/gorm"
 )
 
 type ProjectUsecase interface {
@@ -19,8 +18,8 @@ type ProjectUsecase interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Project, error)
 	FindAll(ctx context.Context, paginationQuery *pagination.Pagination) ([]entity.Project, *pagination.Pagination, error)
 	Create(ctx context.Context, newProject *entity.Project) (*entity.Project, error)
-	Update(ctx context.Context, id uuid.UUID, updateProject *entity.Project) (*entity.Project, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any, tags []tagEntity.Tag) (*entity.Project, error)
+	Delete(ctx context.Context, id uuid.UUID, version *int64) error
 }
 
 type projectUsecase struct {
@@ -35,34 +34,40 @@ func (p *projectUsecase) FindBySlug(ctx context.Context, slug string) (*entity.P
 
 	project, err := p.projectRepository.FindBySlug(ctx, slug)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errorhandler.NotFoundError{Message: "project not found"}
-		}
-		return nil, errorhandler.InternalServerError{Message: err.Error()}
+		return nil, dberror.Translate(err, "project")
 	}
 
 	return project, nil
 }
 
 func (p *projectUsecase) FindByUserID(ctx context.Context, userID uuid.UUID, paginationQuery *pagination.Pagination) ([]entity.Project, *pagination.Pagination, error) {
-	return p.projectRepository.FindByUserID(ctx, userID, paginationQuery)
+
+	projects, pagination, err := p.projectRepository.FindByUserID(ctx, userID, paginationQuery)
+	if err != nil {
+		return nil, nil, dberror.Translate(err, "project")
+	}
+
+	return projects, pagination, nil
 }
 
 func (p *projectUsecase) FindByID(ctx context.Context, id uuid.UUID) (*entity.Project, error) {
 
 	project, err := p.projectRepository.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errorhandler.NotFoundError{Message: "project not found"}
-		}
-		return nil, errorhandler.InternalServerError{Message: err.Error()}
+		return nil, dberror.Translate(err, "project")
 	}
 
 	return project, nil
 }
 
 func (p *projectUsecase) FindAll(ctx context.Context, paginationQuery *pagination.Pagination) ([]entity.Project, *pagination.Pagination, error) {
-	return p.projectRepository.FindAll(ctx, paginationQuery)
+
+	projects, pagination, err := p.projectRepository.FindAll(ctx, paginationQuery)
+	if err != nil {
+		return nil, nil, dberror.Translate(err, "project")
+	}
+
+	return projects, pagination, nil
 }
 
 func (p *projectUsecase) Create(ctx context.Context, newProject *entity.Project) (*entity.Project, error) {
@@ -73,25 +78,33 @@ func (p *projectUsecase) Create(ctx context.Context, newProject *entity.Project)
 
 	project, err := p.projectRepository.Create(ctx, newProject)
 	if err != nil {
-		return nil, err
+		return nil, dberror.Translate(err, "project")
 	}
 	return project, nil
 }
 
-func (p *projectUsecase) Update(ctx context.Context, id uuid.UUID, updateProject *entity.Project) (*entity.Project, error) {
+func (p *projectUsecase) Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any, tags []tagEntity.Tag) (*entity.Project, error) {
 
-	if updateProject.Title != "" {
-		updateProject.Slug = slug.ToSlug(updateProject.Title)
+	// A new title regenerates the slug unless the patch sets one explicitly.
+	if title, ok := updates["title"].(string); ok {
+		if _, ok := updates["slug"]; !ok {
+			updates["slug"] = slug.ToSlug(title)
+		}
 	}
 
-	project, err :=  p.projectRepository.Update(ctx, id, updateProject)
+	project, err := p.projectRepository.Update(ctx, id, version, updates, tags)
 	if err != nil {
-		return nil, err
+		return nil, dberror.Translate(err, "project")
 	}
 
 	return project, nil
 }
 
-func (p *projectUsecase) Delete(ctx context.Context, id uuid.UUID) error {
-	return p.projectRepository.Delete(ctx, id)
+func (p *projectUsecase) Delete(ctx context.Context, id uuid.UUID, version *int64) error {
+
+	if err := p.projectRepository.Delete(ctx, id, version); err != nil {
+		return dberror.Translate(err, "project")
+	}
+
+	return nil
 }
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"html"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/internal/modules/search/entity"
+)
+
+// highlighter marks the matched words of an escaped snippet.
+var highlighter = strings.NewReplacer(entity.HighlightStart, "<mark>", entity.HighlightStop, "</mark>")
+
+type SearchResultResponse struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id"`
+	// Title is the project title for projects, logs and comments, the name for tags and the
+	// full name for profiles.
+	Title string `json:"title"`
+	// Snippet is HTML: the text around the matches, escaped, with the matched words in <mark>.
+	Snippet     string     `json:"snippet"`
+	Rank        float64    `json:"rank"`
+	ProjectID   *uuid.UUID `json:"project_id,omitempty"`
+	ProjectSlug *string    `json:"project_slug,omitempty"`
+	LogID       *uuid.UUID `json:"log_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func NewSearchResultResponse(result *entity.Result) SearchResultResponse {
+	return SearchResultResponse{
+		Type:        result.Type,
+		ID:          result.ID,
+		Title:       result.Title,
+		Snippet:     highlighter.Replace(html.EscapeString(result.Snippet)),
+		Rank:        result.Rank,
+		ProjectID:   result.ProjectID,
+		ProjectSlug: result.ProjectSlug,
+		LogID:       result.LogID,
+		CreatedAt:   result.CreatedAt,
+	}
+}
+
+func NewSearchResultResponses(results []entity.Result) []SearchResultResponse {
+	res := make([]SearchResultResponse, 0, len(results))
+	for i := range results {
+		res = append(res, NewSearchResultResponse(&results[i]))
+	}
+	return res
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of search results, one per searchable table.
+const (
+	TypeProject = "project"
+	TypeLog     = "log"
+	TypeComment = "comment"
+	TypeTag     = "tag"
+	TypeProfile = "profile"
+)
+
+// Types lists every type of search result, in the order they are searched.
+var Types = []string{TypeProject, TypeLog, TypeComment, TypeTag, TypeProfile}
+
+// HighlightStart and HighlightStop surround the matched words in a snippet. They are control
+// characters rather than markup so the snippet can be escaped before the markup is added.
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// Result is one match of a search. It is not a table: results are read from every searchable
+// table at once.
+type Result struct {
+	Type    string
+	ID      uuid.UUID
+	Title   string
+	Snippet string
+	Rank    float64
+	// ProjectID, LogID and ProjectSlug locate logs and comments; ProjectID and ProjectSlug are
+	// also set for projects.
+	ProjectID   *uuid.UUID
+	LogID       *uuid.UUID
+	ProjectSlug *string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/internal/modules/search/dto"
+	"github.com/revandpratama/lognest/internal/modules/search/usecase"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"github.com/revandpratama/lognest/pkg/httpcache"
+	"github.com/revandpratama/lognest/pkg/pagination"
+	"github.com/revandpratama/lognest/pkg/response"
+)
+
+// SearchHandler defines the HTTP handler interface for search.
+type SearchHandler interface {
+	Search(c *fiber.Ctx) error
+}
+
+type searchHandler struct {
+	usecase usecase.SearchUsecase
+}
+
+// NewSearchHandler creates a new instance of SearchHandler.
+func NewSearchHandler(usecase usecase.SearchUsecase) SearchHandler {
+	return &searchHandler{usecase: usecase}
+}
+
+// Search serves GET /search?q=...&type=project,log with the usual limit and page parameters.
+func (h *searchHandler) Search(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
+	defer cancel()
+
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
+	}
+
+	paginationQuery, err := pagination.Parse(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	var types []string
+	if typeQuery := c.Query("type"); typeQuery != "" {
+		for _, resultType := range strings.Split(typeQuery, ",") {
+			types = append(types, strings.TrimSpace(resultType))
+		}
+	}
+
+	results, pagination, err := h.usecase.Search(ctx, userID, c.Query("q"), types, paginationQuery)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	res := dto.NewSearchResultResponses(results)
+
+	etag.SetWeak(c, []any{res, pagination})
+	if httpcache.NotModified(c, httpcache.Private, time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "search results found", res, pagination)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/internal/middlewares"
+	authUsecase "github.com/revandpratama/lognest/internal/modules/auth/usecase"
+	"github.com/revandpratama/lognest/internal/modules/search/entity"
+	"github.com/revandpratama/lognest/pkg/pagination"
+	"github.com/revandpratama/lognest/pkg/token"
+)
+
+// sessionAuth accepts every session; the other AuthUsecase methods are not reached by these tests.
+type sessionAuth struct {
+	authUsecase.AuthUsecase
+}
+
+func (sessionAuth) CheckSession(context.Context, *token.CustomClaims, string, string) error {
+	return nil
+}
+
+// recordingSearch records the viewer it was asked to search for.
+type recordingSearch struct {
+	viewerID uuid.UUID
+}
+
+func (s *recordingSearch) Search(ctx context.Context, viewerID uuid.UUID, text string, types []string, paginationQuery *pagination.Pagination) ([]entity.Result, *pagination.Pagination, error) {
+	s.viewerID = viewerID
+	return nil, paginationQuery, nil
+}
+
+func signedAccessToken(t *testing.T, userID string) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, token.CustomClaims{
+		UserID:           userID,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	}).SignedString([]byte(config.ENV.JWT_SECRET))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestSearchThroughAuthMiddleware(t *testing.T) {
+	previousAlgorithm, previousSecret := config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET
+	config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET = "HS256", "test-secret"
+	t.Cleanup(func() {
+		config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET = previousAlgorithm, previousSecret
+	})
+
+	usecase := &recordingSearch{}
+	app := fiber.New()
+	app.Get("/search", middlewares.AuthMiddleware(sessionAuth{}), NewSearchHandler(usecase).Search)
+
+	userID := uuid.New()
+	req := httptest.NewRequest(fiber.MethodGet, "/search?q=go", nil)
+	req.Header.Set(fiber.HeaderCookie, token.AccessTokenCookieName+"=Bearer "+signedAccessToken(t, userID.String()))
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+	}
+	if usecase.viewerID != userID {
+		t.Errorf("viewer = %s, want the authenticated user %s", usecase.viewerID, userID)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/search?q=go", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUnauthorized {
+		t.Errorf("status without a token = %d, want %d", resp.StatusCode, fiber.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/internal/modules/search/entity"
+	"github.com/revandpratama/lognest/pkg/pagination"
+	"gorm.io/gorm"
+)
+
+// SearchRepository defines the interface for full-text search across the searchable tables.
+type SearchRepository interface {
+	Search(ctx context.Context, viewerID uuid.UUID, text string, types []string, paginationQuery *pagination.Pagination) ([]entity.Result, error)
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository creates a new instance of SearchRepository.
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// visibleProject matches the projects the viewer may see: public ones and their own. A project
+// without is_public is public, as everywhere else.
+const visibleProject = "p.deleted_at IS NULL AND (p.is_public IS NOT FALSE OR p.user_profile_id = @viewer)"
+
+// searches select the matches of one type. document is the text the snippet is taken from.
+var searches = map[string]string{
+	entity.TypeProject: `
+		SELECT 'project' AS type, p.id, p.title, coalesce(nullif(p.description, ''), p.title) AS document,
+		       ts_rank(p.search_vector, q.query) AS rank, p.id AS project_id, NULL::uuid AS log_id,
+		       p.slug AS project_slug, p.created_at
+		FROM {{schema}}.projects p CROSS JOIN q
+		WHERE p.search_vector @@ q.query AND ` + visibleProject,
+	entity.TypeLog: `
+		SELECT 'log', l.id, p.title, l.content, ts_rank(l.search_vector, q.query), l.project_id, l.id,
+		       p.slug, l.created_at
+		FROM {{schema}}.logs l
+		JOIN {{schema}}.projects p ON p.id = l.project_id CROSS JOIN q
+		WHERE l.search_vector @@ q.query AND l.deleted_at IS NULL AND ` + visibleProject,
+	entity.TypeComment: `
+		SELECT 'comment', c.id, p.title, c.body, ts_rank(c.search_vector, q.query), l.project_id, c.log_id,
+		       p.slug, c.created_at
+		FROM {{schema}}.comments c
+		JOIN {{schema}}.logs l ON l.id = c.log_id AND l.deleted_at IS NULL
+		JOIN {{schema}}.projects p ON p.id = l.project_id CROSS JOIN q
+		WHERE c.search_vector @@ q.query AND c.deleted_at IS NULL AND ` + visibleProject,
+	entity.TypeTag: `
+		SELECT 'tag', t.id, t.name, t.name, ts_rank(t.search_vector, q.query), NULL, NULL, NULL, t.created_at
+		FROM {{schema}}.tags t CROSS JOIN q
+		WHERE t.search_vector @@ q.query AND t.deleted_at IS NULL`,
+	entity.TypeProfile: `
+		SELECT 'profile', u.user_id, concat_ws(' ', u.first_name, u.last_name),
+		       coalesce(nullif(u.bio, ''), concat_ws(' ', u.first_name, u.last_name)),
+		       ts_rank(u.search_vector, q.query), NULL, NULL, NULL, u.created_at
+		FROM {{schema}}.user_profiles u CROSS JOIN q
+		WHERE u.search_vector @@ q.query AND u.deleted_at IS NULL`,
+}
+
+// headlineOptions configure the snippets: about 15 to 35 words around the best match, with the
+// matched words between the highlight markers.
+var headlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MinWords=15, MaxWords=35, MaxFragments=2", entity.HighlightStart, entity.HighlightStop)
+
+// Search runs text as a web search query (quoted phrases, OR and -word are supported) against the
+// given types of results and returns one page of them, best match first. Snippets are only
+// computed for the rows of the page.
+func (r *searchRepository) Search(ctx context.Context, viewerID uuid.UUID, text string, types []string, paginationQuery *pagination.Pagination) ([]entity.Result, error) {
+	selects := make([]string, 0, len(types))
+	for _, resultType := range types {
+		selects = append(selects, searches[resultType])
+	}
+
+	query := `
+		WITH q AS (SELECT websearch_to_tsquery('simple', @text) AS query)
+		SELECT results.type, results.id, results.title,
+		       ts_headline('simple', results.document, q.query, @options) AS snippet,
+		       results.rank, results.project_id, results.log_id, results.project_slug, results.created_at
+		FROM (` + strings.Join(selects, "\n\t\tUNION ALL") + `
+		) results CROSS JOIN q
+		ORDER BY results.rank DESC, results.created_at DESC, results.id
+		LIMIT @limit OFFSET @offset`
+	query = strings.ReplaceAll(query, "{{schema}}", config.ENV.LOGNEST_SCHEMA)
+
+	var results []entity.Result
+	err := r.db.WithContext(ctx).Raw(query,
+		sql.Named("text", text),
+		sql.Named("viewer", viewerID),
+		sql.Named("options", headlineOptions),
+		sql.Named("limit", paginationQuery.GetLimit()),
+		sql.Named("offset", paginationQuery.GetOffset()),
+	).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/internal/modules/search/entity"
+	"github.com/revandpratama/lognest/pkg/pagination"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// searchSQL returns the statement Search builds for viewerID, with its variables inlined, without
+// a database server.
+func searchSQL(t *testing.T, viewerID uuid.UUID, types ...string) string {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	var statement string
+	err = db.Callback().Row().After("gorm:row").Register("test:capture", func(tx *gorm.DB) {
+		statement = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	// Scanning is not supported in a dry run; the statement is built by then.
+	_, err = NewSearchRepository(db).Search(context.Background(), viewerID, "go", types, &pagination.Pagination{})
+	if err != nil && !errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+		t.Fatalf("Search: %v", err)
+	}
+	return statement
+}
+
+func TestSearchExcludesOtherUsersPrivateProjects(t *testing.T) {
+	previousSchema := config.ENV.LOGNEST_SCHEMA
+	config.ENV.LOGNEST_SCHEMA = "lognest"
+	t.Cleanup(func() { config.ENV.LOGNEST_SCHEMA = previousSchema })
+
+	viewerID := uuid.New()
+	visible := "p.deleted_at IS NULL AND (p.is_public IS NOT FALSE OR p.user_profile_id = '" + viewerID.String() + "')"
+
+	// Every result that belongs to a project is only returned when the viewer may see the project.
+	for _, resultType := range []string{entity.TypeProject, entity.TypeLog, entity.TypeComment} {
+		t.Run(resultType, func(t *testing.T) {
+			statement := searchSQL(t, viewerID, resultType)
+			if !strings.Contains(statement, visible) {
+				t.Errorf("SQL %q does not restrict projects to %q", statement, visible)
+			}
+			if !strings.Contains(statement, "FROM lognest.") {
+				t.Errorf("SQL %q is not qualified with the configured schema", statement)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/internal/modules/search/entity"
+	"github.com/revandpratama/lognest/internal/modules/search/repository"
+	"github.com/revandpratama/lognest/pkg/dberror"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/pagination"
+)
+
+const (
+	maxQueryLength = 256
+	// maxLimit bounds a page of results, since every result costs a snippet.
+	maxLimit = 50
+)
+
+// SearchUsecase defines the business logic interface for search.
+type SearchUsecase interface {
+	Search(ctx context.Context, viewerID uuid.UUID, text string, types []string, paginationQuery *pagination.Pagination) ([]entity.Result, *pagination.Pagination, error)
+}
+
+type searchUsecase struct {
+	repo repository.SearchRepository
+}
+
+// NewSearchUsecase creates a new instance of SearchUsecase.
+func NewSearchUsecase(repo repository.SearchRepository) SearchUsecase {
+	return &searchUsecase{repo: repo}
+}
+
+// Search finds the results of the given types matching text that viewerID may see. Without types,
+// every type is searched.
+func (u *searchUsecase) Search(ctx context.Context, viewerID uuid.UUID, text string, types []string, paginationQuery *pagination.Pagination) ([]entity.Result, *pagination.Pagination, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil, errorhandler.BadRequestError{Message: "q is required"}
+	}
+	if utf8.RuneCountInString(text) > maxQueryLength {
+		return nil, nil, errorhandler.BadRequestError{Message: fmt.Sprintf("q must be at most %d characters", maxQueryLength)}
+	}
+
+	if len(types) == 0 {
+		types = entity.Types
+	}
+	for _, resultType := range types {
+		if !slices.Contains(entity.Types, resultType) {
+			return nil, nil, errorhandler.BadRequestError{Message: fmt.Sprintf("type must be one of %s", strings.Join(entity.Types, ", "))}
+		}
+	}
+	types = slices.Compact(slices.Sorted(slices.Values(types)))
+
+	if paginationQuery.GetLimit() > maxLimit {
+		paginationQuery.Limit = maxLimit
+	}
+
+	results, err := u.repo.Search(ctx, viewerID, text, types, paginationQuery)
+	if err != nil {
+		return nil, nil, dberror.Translate(err, "search result")
+	}
+	return results, paginationQuery, nil
+}
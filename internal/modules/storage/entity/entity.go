@@ -6,7 +6,7 @@ import (
 
 // Storage represents the data structure for a storage.
 type Storage struct {
-	PathName string                `json:"path_name" form:"path_name"`
+	PathName string                `json:"path_name" form:"path_name" validate:"required,filepath_prefix"`
 	File     *multipart.FileHeader `form:"file"`
 	Image    *multipart.FileHeader `form:"image"`
 }
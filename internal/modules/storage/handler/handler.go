@@ -10,6 +10,7 @@ import (
 	"github.com/revandpratama/lognest/internal/modules/storage/usecase"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
 	"github.com/revandpratama/lognest/pkg/response"
+	"github.com/revandpratama/lognest/pkg/validation"
 )
 
 // StorageHandler defines the HTTP handler interface for a Storage.
@@ -33,12 +34,11 @@ func (h *storageHandler) Upload(c *fiber.Ctx) error {
 	defer cancel()
 
 	var storage entity.Storage
-	pathName := c.FormValue("path_name")
-	if pathName == "" {
-		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "path_name is required"}, nil)
-	}
+	storage.PathName = c.FormValue("path_name")
 
-	storage.PathName = pathName
+	if errs := validation.Struct(c, &storage); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
 
 	file, _ := c.FormFile("file")
 	if file != nil {
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/internal/modules/tag/entity"
+	"github.com/revandpratama/lognest/pkg/patch"
+)
+
+// CreateTagRequest lists the fields a client may set when creating a tag.
+type CreateTagRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+func (r *CreateTagRequest) ToEntity() *entity.Tag {
+	return &entity.Tag{Name: r.Name}
+}
+
+// UpdateTagRequest is a JSON Merge Patch of the fields a client may change on a tag.
+type UpdateTagRequest struct {
+	Name patch.Field[string] `json:"name" validate:"required,min=1,max=255"`
+}
+
+// ToUpdates returns the columns present in the patch, keyed by column name.
+func (r *UpdateTagRequest) ToUpdates() map[string]any {
+	updates := map[string]any{}
+	if r.Name.Set {
+		updates["name"] = r.Name.Value
+	}
+	return updates
+}
+
+type TagResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewTagResponse(tag *entity.Tag) TagResponse {
+	return TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+func NewTagResponses(tags []entity.Tag) []TagResponse {
+	res := make([]TagResponse, 0, len(tags))
+	for i := range tags {
+		res = append(res, NewTagResponse(&tags[i]))
+	}
+	return res
+}
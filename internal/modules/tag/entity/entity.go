@@ -12,10 +12,13 @@ import (
 // Tag represents the data structure for a tag.
 type Tag struct {
 	ID          uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
-	Name        string         `gorm:"type:varchar(255);not null" json:"name" validate:"required,min=1,max=255"`
+	Name        string         `gorm:"type:varchar(255);not null" json:"name"`
 	CreatedAt   time.Time      `gorm:"not null" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"not null" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// SearchVector indexes Name for search and is generated by Postgres.
+	SearchVector string `gorm:"type:tsvector;index:,type:gin;->:false;<-:false" json:"-"`
 }
 
 // TableName sets the table name for the Tag.
@@ -6,11 +6,14 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/revandpratama/lognest/internal/modules/tag/entity"
+	"github.com/revandpratama/lognest/internal/modules/tag/dto"
 	"github.com/revandpratama/lognest/internal/modules/tag/usecase"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"github.com/revandpratama/lognest/pkg/httpcache"
 	"github.com/revandpratama/lognest/pkg/pagination"
 	"github.com/revandpratama/lognest/pkg/response"
+	"github.com/revandpratama/lognest/pkg/validation"
 )
 
 // TagHandler defines the HTTP handler interface for a Tag.
@@ -32,12 +35,12 @@ func NewTagHandler(usecase usecase.TagUsecase) TagHandler {
 }
 
 func (h *tagHandler) FindAll(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	paginationQuery := new(pagination.Pagination)
-	if err := c.QueryParser(paginationQuery); err != nil {
-		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
+	paginationQuery, err := pagination.Parse(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
 	}
 
 	tags, pagination, err := h.usecase.FindAll(ctx, paginationQuery)
@@ -45,12 +48,22 @@ func (h *tagHandler) FindAll(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Paginated(c, fiber.StatusOK, "tags found", tags, pagination)
+	res := make([]dto.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, dto.NewTagResponse(tag))
+	}
+
+	etag.SetWeak(c, []any{res, pagination})
+	if httpcache.NotModified(c, httpcache.Public, time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "tags found", res, pagination)
 
 }
 
 func (h *tagHandler) FindByID(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -68,31 +81,42 @@ func (h *tagHandler) FindByID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "tag found", tag)
+	res := dto.NewTagResponse(tag)
+
+	etag.SetWeak(c, res)
+	if httpcache.NotModified(c, httpcache.Public, tag.UpdatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Success(c, fiber.StatusOK, "tag found", res)
 
 }
 
 func (h *tagHandler) Create(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
-	var newTag entity.Tag
+	var newTag dto.CreateTagRequest
 	if err := c.BodyParser(&newTag); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	tag, err := h.usecase.Create(ctx, &newTag)
+	if errs := validation.Struct(c, &newTag); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	tag, err := h.usecase.Create(ctx, newTag.ToEntity())
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusCreated, "tag created", tag)
+	return response.Success(c, fiber.StatusCreated, "tag created", dto.NewTagResponse(tag))
 
 }
 
 
 func (h *tagHandler) Update(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -105,21 +129,25 @@ func (h *tagHandler) Update(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	var updateTag entity.Tag
+	var updateTag dto.UpdateTagRequest
 	if err := c.BodyParser(&updateTag); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	tag, err := h.usecase.Update(ctx, id, &updateTag)
+	if errs := validation.Partial(c, &updateTag); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	tag, err := h.usecase.Update(ctx, id, updateTag.ToUpdates())
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "tag updated", tag)
+	return response.Success(c, fiber.StatusOK, "tag updated", dto.NewTagResponse(tag))
 }
 
 func (h *tagHandler) Delete(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
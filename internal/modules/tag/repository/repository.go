@@ -14,7 +14,7 @@ type TagRepository interface {
 	FindAll(ctx context.Context, paginationQuery *pagination.Pagination) ([]*entity.Tag, *pagination.Pagination, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error)
 	Create(ctx context.Context, newTag *entity.Tag) (*entity.Tag, error)
-	Update(ctx context.Context, id uuid.UUID, updateTag *entity.Tag) (*entity.Tag, error)
+	Update(ctx context.Context, id uuid.UUID, updates map[string]any) (*entity.Tag, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
@@ -32,9 +32,20 @@ func (r *tagRepository) FindAll(ctx context.Context, paginationQuery *pagination
 	
 	allowedSortColumns := []string{
 		"created_at",
+		"name",
+	}
+
+	allowedFilters := pagination.AllowedFilters{
+		"name":       {Column: "name"},
+		"created_at": {Column: "created_at", Type: pagination.Time},
+	}
+
+	query, err := pagination.ApplyFilters(r.db.WithContext(ctx), paginationQuery, allowedFilters)
+	if err != nil {
+		return nil, nil, err
 	}
 	
-	paginatedDB := pagination.Paginate(r.db.WithContext(ctx), paginationQuery, &tags, allowedSortColumns)
+	paginatedDB := pagination.Paginate(query, paginationQuery, &tags, allowedSortColumns)
 	
 	if err := paginatedDB.Find(&tags).Error; err != nil {
 		return nil, nil, err
@@ -56,9 +67,16 @@ func (r *tagRepository) Create(ctx context.Context, newTag *entity.Tag) (*entity
 	return newTag, err
 }
 
-func (r *tagRepository) Update(ctx context.Context, id uuid.UUID, updateTag *entity.Tag) (*entity.Tag, error) {
-	err := r.db.WithContext(ctx).Model(&entity.Tag{}).Where("id = ?", id).Updates(updateTag).Error
-	return updateTag, err
+func (r *tagRepository) Update(ctx context.Context, id uuid.UUID, updates map[string]any) (*entity.Tag, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Tag{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return r.FindByID(ctx, id)
 }
 
 func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/internal/modules/tag/entity"
 	"github.com/revandpratama/lognest/internal/modules/tag/repository"
+	"github.com/revandpratama/lognest/pkg/dberror"
 	"github.com/revandpratama/lognest/pkg/pagination"
 )
 
@@ -14,7 +15,7 @@ type TagUsecase interface {
 	FindAll(ctx context.Context, paginationQuery *pagination.Pagination) ([]*entity.Tag, *pagination.Pagination, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error)
 	Create(ctx context.Context, newTag *entity.Tag) (*entity.Tag, error)
-	Update(ctx context.Context, id uuid.UUID, updateTag *entity.Tag) (*entity.Tag, error)
+	Update(ctx context.Context, id uuid.UUID, updates map[string]any) (*entity.Tag, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
@@ -28,21 +29,40 @@ func NewTagUsecase(repo repository.TagRepository) TagUsecase {
 }
 
 func (u *tagUsecase) FindAll(ctx context.Context, paginationQuery *pagination.Pagination) ([]*entity.Tag, *pagination.Pagination, error) {
-	return u.repo.FindAll(ctx, paginationQuery)
+	tags, pagination, err := u.repo.FindAll(ctx, paginationQuery)
+	if err != nil {
+		return nil, nil, dberror.Translate(err, "tag")
+	}
+	return tags, pagination, nil
 }
 
 func (u *tagUsecase) FindByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error) {
-	return u.repo.FindByID(ctx, id)
+	tag, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, dberror.Translate(err, "tag")
+	}
+	return tag, nil
 }
 
 func (u *tagUsecase) Create(ctx context.Context, newTag *entity.Tag) (*entity.Tag, error) {
-	return u.repo.Create(ctx, newTag)
+	tag, err := u.repo.Create(ctx, newTag)
+	if err != nil {
+		return nil, dberror.Translate(err, "tag")
+	}
+	return tag, nil
 }
 
-func (u *tagUsecase) Update(ctx context.Context, id uuid.UUID, updateTag *entity.Tag) (*entity.Tag, error) {
-	return u.repo.Update(ctx, id, updateTag)
+func (u *tagUsecase) Update(ctx context.Context, id uuid.UUID, updates map[string]any) (*entity.Tag, error) {
+	tag, err := u.repo.Update(ctx, id, updates)
+	if err != nil {
+		return nil, dberror.Translate(err, "tag")
+	}
+	return tag, nil
 }
 
 func (u *tagUsecase) Delete(ctx context.Context, id uuid.UUID) error {
-	return u.repo.Delete(ctx, id)
+	if err := u.repo.Delete(ctx, id); err != nil {
+		return dberror.Translate(err, "tag")
+	}
+	return nil
 }
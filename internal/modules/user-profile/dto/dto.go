@@ -3,47 +3,153 @@ package dto
 import (
 	"time"
 
-	"gorm.io/gorm"
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/internal/modules/user-profile/entity"
+	"github.com/revandpratama/lognest/pkg/patch"
 )
 
 type MeResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    User   `json:"data"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    entity.User `json:"data"`
 }
 
-type User struct {
-	// ID         string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
-	ID         string `gorm:"primaryKey;type:uuid" json:"id"`
-	Email      string `gorm:"uniqueIndex;not null" json:"email"`
-	Password   string `gorm:"" json:"-"` // hashed password; can be empty for OAuth users
-	FirstName  string `gorm:"column:first_name" json:"first_name"`
-	LastName   string `gorm:"column:last_name" json:"last_name"`
-	AvatarPath string `gorm:"size:500" json:"avatar_path"`
+// CreateUserProfileRequest lists the fields a client may set when creating a profile. The ID and
+// email come from the authenticated account.
+type CreateUserProfileRequest struct {
+	Bio        string `json:"bio"`
+	FirstName  string `json:"first_name" validate:"max=255"`
+	LastName   string `json:"last_name" validate:"max=255"`
+	AvatarPath string `json:"avatar_path" validate:"omitempty,filepath_prefix,max=500"`
+}
 
-	Providers []OAuthProvider `gorm:"foreignKey:UserID" json:"providers,omitempty"`
+func (r *CreateUserProfileRequest) ToEntity(userID uuid.UUID, email string) *entity.UserProfile {
+	return &entity.UserProfile{
+		UserID:     userID,
+		Email:      email,
+		Bio:        r.Bio,
+		FirstName:  r.FirstName,
+		LastName:   r.LastName,
+		AvatarPath: r.AvatarPath,
+	}
+}
 
-	EmailVerified      bool      `gorm:"default:false" json:"email_verified"`
-	VerificationToken  string    `gorm:"size:255" json:"-"`
-	VerificationSentAt time.Time `json:"-"`
+// UpdateUserProfileRequest is a JSON Merge Patch of the fields a client may change on a profile.
+type UpdateUserProfileRequest struct {
+	Bio        patch.Field[string] `json:"bio"`
+	FirstName  patch.Field[string] `json:"first_name" validate:"omitempty,max=255"`
+	LastName   patch.Field[string] `json:"last_name" validate:"omitempty,max=255"`
+	AvatarPath patch.Field[string] `json:"avatar_path" validate:"omitempty,filepath_prefix,max=500"`
+}
+
+// ToUpdates returns the columns present in the patch, keyed by column name. Null clears a field.
+func (r *UpdateUserProfileRequest) ToUpdates() map[string]any {
+	updates := map[string]any{}
+	if r.Bio.Set {
+		updates["bio"] = r.Bio.Value
+	}
+	if r.FirstName.Set {
+		updates["first_name"] = r.FirstName.Value
+	}
+	if r.LastName.Set {
+		updates["last_name"] = r.LastName.Value
+	}
+	if r.AvatarPath.Set {
+		updates["avatar_path"] = r.AvatarPath.Value
+	}
+	return updates
+}
 
-	MFAEnabled bool   `gorm:"default:false" json:"mfa_enabled"`
-	MFASecret  string `gorm:"size:255" json:"-"`
+type UserProfileResponse struct {
+	ID             uuid.UUID `json:"id"`
+	Bio            string    `json:"bio"`
+	Email          string    `json:"email"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	AvatarPath     string    `json:"avatar_path"`
+	FollowerCount  int       `json:"follower_count"`
+	FollowingCount int       `json:"following_count"`
+	Version        int64     `json:"version"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 
-	RoleID uint `gorm:"not null" json:"role_id"`
+	Following []UserProfileResponse `json:"following,omitempty"`
+	Followers []UserProfileResponse `json:"followers,omitempty"`
+
+	User *UserResponse `json:"user,omitempty"`
+}
+
+// UserResponse is the account as shown to its owner, without auth4me's internal columns.
+type UserResponse struct {
+	ID            string             `json:"id"`
+	Email         string             `json:"email"`
+	FirstName     string             `json:"first_name"`
+	LastName      string             `json:"last_name"`
+	AvatarPath    string             `json:"avatar_path"`
+	Providers     []ProviderResponse `json:"providers,omitempty"`
+	EmailVerified bool               `json:"email_verified"`
+	MFAEnabled    bool               `json:"mfa_enabled"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
 
-	// Timestamps
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+type ProviderResponse struct {
+	Provider  string    `json:"provider"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
-type OAuthProvider struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	UserID       string    `gorm:"type:uuid;index" json:"user_id"`
-	Provider     string    `gorm:"size:100;index" json:"provider"`
-	ProviderID   string    `gorm:"size:255;index" json:"provider_id"`
-	AccessToken  string    `gorm:"size:500" json:"-"`
-	RefreshToken string    `gorm:"size:500" json:"-"`
-	ExpiresAt    time.Time `json:"expires_at"`
+func NewUserProfileResponse(userProfile *entity.UserProfile) UserProfileResponse {
+	res := UserProfileResponse{
+		ID:             userProfile.UserID,
+		Bio:            userProfile.Bio,
+		Email:          userProfile.Email,
+		FirstName:      userProfile.FirstName,
+		LastName:       userProfile.LastName,
+		AvatarPath:     userProfile.AvatarPath,
+		FollowerCount:  userProfile.FollowerCount,
+		FollowingCount: userProfile.FollowingCount,
+		Version:        userProfile.Version,
+		CreatedAt:      userProfile.CreatedAt,
+		UpdatedAt:      userProfile.UpdatedAt,
+		Following:      NewUserProfileResponses(userProfile.Following),
+		Followers:      NewUserProfileResponses(userProfile.Followers),
+	}
+
+	if userProfile.User.ID != "" {
+		user := NewUserResponse(&userProfile.User)
+		res.User = &user
+	}
+
+	return res
+}
+
+func NewUserProfileResponses(userProfiles []entity.UserProfile) []UserProfileResponse {
+	res := make([]UserProfileResponse, 0, len(userProfiles))
+	for i := range userProfiles {
+		res = append(res, NewUserProfileResponse(&userProfiles[i]))
+	}
+	return res
+}
+
+func NewUserResponse(user *entity.User) UserResponse {
+	res := UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		AvatarPath:    user.AvatarPath,
+		EmailVerified: user.EmailVerified,
+		MFAEnabled:    user.MFAEnabled,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+
+	for _, provider := range user.Providers {
+		res.Providers = append(res.Providers, ProviderResponse{
+			Provider:  provider.Provider,
+			ExpiresAt: provider.ExpiresAt,
+		})
+	}
+
+	return res
 }
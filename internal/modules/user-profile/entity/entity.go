@@ -6,7 +6,6 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/config"
-	"github.com/revandpratama/lognest/internal/modules/user-profile/dto"
 	"gorm.io/gorm"
 )
 
@@ -23,16 +22,21 @@ type UserProfile struct {
 	FollowerCount  int `gorm:"default:0" json:"follower_count"`
 	FollowingCount int `gorm:"default:0" json:"following_count"`
 
+	Version int64 `gorm:"not null;default:1" json:"version"`
+
 	CreatedAt time.Time      `gorm:"not null" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"not null" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Following []UserProfile `gorm:"many2many:user_followers;foreignKey:UserID;joinForeignKey:FollowerID;References:UserID;joinReferences:FollowingID" json:"following,omitempty"`
+	// SearchVector indexes the name and bio for search and is generated by Postgres.
+	SearchVector string `gorm:"type:tsvector;index:,type:gin;->:false;<-:false" json:"-"`
+
+	Following []UserProfile `gorm:"many2many:lognest.user_followers;foreignKey:UserID;joinForeignKey:FollowerID;References:UserID;joinReferences:FollowingID" json:"following,omitempty"`
 
 	// Users that follow this user
-	Followers []UserProfile `gorm:"many2many:user_followers;foreignKey:UserID;joinForeignKey:FollowingID;References:UserID;joinReferences:FollowerID" json:"followers,omitempty"`
+	Followers []UserProfile `gorm:"many2many:lognest.user_followers;foreignKey:UserID;joinForeignKey:FollowingID;References:UserID;joinReferences:FollowerID" json:"followers,omitempty"`
 
-	User dto.User `gorm:"-" json:"user,omitzero"`
+	User User `gorm:"-" json:"user,omitzero"`
 }
 
 // type User struct {
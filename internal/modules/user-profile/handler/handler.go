@@ -6,10 +6,13 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/revandpratama/lognest/internal/modules/user-profile/entity"
+	"github.com/revandpratama/lognest/internal/modules/user-profile/dto"
 	"github.com/revandpratama/lognest/internal/modules/user-profile/usecase"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"github.com/revandpratama/lognest/pkg/httpcache"
 	"github.com/revandpratama/lognest/pkg/response"
+	"github.com/revandpratama/lognest/pkg/validation"
 )
 
 // UserProfileHandler defines the HTTP handler interface for a UserProfile.
@@ -30,19 +33,38 @@ func NewUserProfileHandler(usecase usecase.UserProfileUsecase) UserProfileHandle
 }
 
 func (h *userprofileHandler) Create(c *fiber.Ctx) error {
-	var newUserProfile entity.UserProfile
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return errorhandler.BuildError(c, errorhandler.UnauthorizedError{Message: "unauthorized: userID not found"}, nil)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid userID format"}, nil)
+	}
+
+	email, _ := c.Locals("email").(string)
+
+	var newUserProfile dto.CreateUserProfileRequest
 	if err := c.BodyParser(&newUserProfile); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
-	userProfile, err := h.usecase.Create(c.Context(), &newUserProfile)
+
+	if errs := validation.Struct(c, &newUserProfile); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+	userProfile, err := h.usecase.Create(c.UserContext(), newUserProfile.ToEntity(userID, email))
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
-	return response.Success(c, fiber.StatusCreated, "user profile created", userProfile)
+	res := dto.NewUserProfileResponse(userProfile)
+
+	etag.Set(c, userProfile.Version, res)
+	return response.Success(c, fiber.StatusCreated, "user profile created", res)
 }
 
 func (h *userprofileHandler) FindByID(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -60,11 +82,18 @@ func (h *userprofileHandler) FindByID(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "user profile found", userProfile)
+	res := dto.NewUserProfileResponse(userProfile)
+
+	etag.Set(c, userProfile.Version, res)
+	if httpcache.NotModified(c, httpcache.Private, userProfile.UpdatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Success(c, fiber.StatusOK, "user profile found", res)
 }
 
 func (h *userprofileHandler) Update(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	idStr := c.Params("id")
@@ -77,21 +106,33 @@ func (h *userprofileHandler) Update(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: "invalid id format"}, nil)
 	}
 
-	var updateUserProfile entity.UserProfile
+	version, err := etag.IfMatch(c)
+	if err != nil {
+		return errorhandler.BuildError(c, err, nil)
+	}
+
+	var updateUserProfile dto.UpdateUserProfileRequest
 	if err := c.BodyParser(&updateUserProfile); err != nil {
 		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: err.Error()}, nil)
 	}
 
-	userProfile, err := h.usecase.Update(ctx, id, &updateUserProfile)
+	if errs := validation.Partial(c, &updateUserProfile); errs != nil {
+		return errorhandler.BuildError(c, errorhandler.BadRequestError{Message: validation.Message(c)}, errs)
+	}
+
+	userProfile, err := h.usecase.Update(ctx, id, version, updateUserProfile.ToUpdates())
 	if err != nil {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "user profile updated", userProfile)
+	res := dto.NewUserProfileResponse(userProfile)
+
+	etag.Set(c, userProfile.Version, res)
+	return response.Success(c, fiber.StatusOK, "user profile updated", res)
 }
 
 func (h *userprofileHandler) FindUser(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 	defer cancel()
 
 	tokenStr := c.Cookies("access_token")
@@ -104,5 +145,18 @@ func (h *userprofileHandler) FindUser(c *fiber.Ctx) error {
 		return errorhandler.BuildError(c, err, nil)
 	}
 
-	return response.Success(c, fiber.StatusOK, "user profile found", userProfile)
+	res := dto.NewUserProfileResponse(userProfile)
+
+	// The account comes from auth4me and can change independently of the profile.
+	lastModified := userProfile.UpdatedAt
+	if userProfile.User.UpdatedAt.After(lastModified) {
+		lastModified = userProfile.User.UpdatedAt
+	}
+
+	etag.Set(c, userProfile.Version, res)
+	if httpcache.NotModified(c, httpcache.Private, lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return response.Success(c, fiber.StatusOK, "user profile found", res)
 }
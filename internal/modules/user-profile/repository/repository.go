@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/revandpratama/lognest/internal/modules/user-profile/entity"
+	"github.com/revandpratama/lognest/pkg/etag"
 	"gorm.io/gorm"
 )
 
@@ -12,7 +13,7 @@ import (
 type UserProfileRepository interface {
 	Create(ctx context.Context, newUserProfile *entity.UserProfile) (*entity.UserProfile, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.UserProfile, error)
-	Update(ctx context.Context, id uuid.UUID, updateUserProfile *entity.UserProfile) (*entity.UserProfile, error)
+	Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any) (*entity.UserProfile, error)
 }
 
 type userprofileRepository struct {
@@ -42,7 +43,36 @@ func (r *userprofileRepository) FindByID(ctx context.Context, id uuid.UUID) (*en
 	return &userProfile, nil
 }
 
-func (r *userprofileRepository) Update(ctx context.Context, id uuid.UUID, updateUserProfile *entity.UserProfile) (*entity.UserProfile, error) {
-	err := r.db.WithContext(ctx).Model(&entity.UserProfile{}).Where("user_id = ?", id).Updates(updateUserProfile).Error
-	return updateUserProfile, err
+// Update applies updates to the profile. When version is not nil the update only applies to that
+// version of the profile.
+func (r *userprofileRepository) Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any) (*entity.UserProfile, error) {
+	updates["version"] = gorm.Expr("version + 1")
+
+	query := r.db.WithContext(ctx).Model(&entity.UserProfile{}).Where("user_id = ?", id)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, r.missingOrStale(ctx, id)
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// missingOrStale explains a conditional update that affected no rows: the profile is either gone
+// or at another version.
+func (r *userprofileRepository) missingOrStale(ctx context.Context, id uuid.UUID) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entity.UserProfile{}).Where("user_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return etag.ErrVersionMismatch
 }
@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 
@@ -12,16 +11,16 @@ import (
 	"github.com/revandpratama/lognest/internal/modules/user-profile/dto"
 	"github.com/revandpratama/lognest/internal/modules/user-profile/entity"
 	"github.com/revandpratama/lognest/internal/modules/user-profile/repository"
+	"github.com/revandpratama/lognest/pkg/dberror"
 	"github.com/revandpratama/lognest/pkg/errorhandler"
-	"github.com/rs/zerolog/log"
-	"gorm.io/gorm"
+	"github.com/revandpratama/lognest/pkg/logging"
 )
 
 // UserProfileUsecase defines the business logic interface for a UserProfile.
 type UserProfileUsecase interface {
 	Create(ctx context.Context, newUserProfile *entity.UserProfile) (*entity.UserProfile, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.UserProfile, error)
-	Update(ctx context.Context, id uuid.UUID, updateUserProfile *entity.UserProfile) (*entity.UserProfile, error)
+	Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any) (*entity.UserProfile, error)
 	FindUser(ctx context.Context, tokenStr string) (*entity.UserProfile, error)
 }
 
@@ -40,31 +39,37 @@ func NewUserProfileUsecase(repo repository.UserProfileRepository, httpClient *ht
 
 func (u *userprofileUsecase) Create(ctx context.Context, newUserProfile *entity.UserProfile) (*entity.UserProfile, error) {
 
-	return u.repo.Create(ctx, newUserProfile)
+	userProfile, err := u.repo.Create(ctx, newUserProfile)
+	if err != nil {
+		return nil, dberror.Translate(err, "user profile")
+	}
+
+	return userProfile, nil
 }
 
 func (u *userprofileUsecase) FindByID(ctx context.Context, id uuid.UUID) (*entity.UserProfile, error) {
 	userProfile, err := u.repo.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errorhandler.NotFoundError{Message: "user profile not found"}
-		}
-		return nil, errorhandler.InternalServerError{Message: err.Error()}
+		return nil, dberror.Translate(err, "user profile")
 	}
 	return userProfile, nil
 }
 
-func (u *userprofileUsecase) Update(ctx context.Context, id uuid.UUID, updateUserProfile *entity.UserProfile) (*entity.UserProfile, error) {
-	return u.repo.Update(ctx, id, updateUserProfile)
+func (u *userprofileUsecase) Update(ctx context.Context, id uuid.UUID, version *int64, updates map[string]any) (*entity.UserProfile, error) {
+	userProfile, err := u.repo.Update(ctx, id, version, updates)
+	if err != nil {
+		return nil, dberror.Translate(err, "user profile")
+	}
+	return userProfile, nil
 }
 
 func (u *userprofileUsecase) FindUser(ctx context.Context, tokenStr string) (*entity.UserProfile, error) {
 
 	var url = fmt.Sprintf("%s/api/auth/user", config.ENV.AUTH4ME_URL)
 
-	log.Debug().Msg("tokenStr: " + tokenStr)
+	logging.Ctx(ctx).Debug().Str("token", logging.Redact(tokenStr)).Msg("fetching user from auth4me")
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, errorhandler.InternalServerError{Message: err.Error()}
 	}
@@ -78,10 +83,7 @@ func (u *userprofileUsecase) FindUser(ctx context.Context, tokenStr string) (*en
 	}
 	defer resp.Body.Close()
 
-	reponseByte, _ := json.Marshal(resp.Body)
-	log.Debug().Msg("response: " + string(reponseByte))
-
-	log.Debug().Msg("response: " + resp.Status)
+	logging.Ctx(ctx).Debug().Int("upstream_status", resp.StatusCode).Msg("auth4me user response")
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, errorhandler.InternalServerError{Message: "failed to get user"}
@@ -93,14 +95,16 @@ func (u *userprofileUsecase) FindUser(ctx context.Context, tokenStr string) (*en
 		return nil, errorhandler.InternalServerError{Message: err.Error()}
 	}
 
-	log.Debug().Msg("user: " + user.Data.ID)
+	logging.Ctx(ctx).Debug().Str("auth4me_user_id", user.Data.ID).Msg("auth4me user resolved")
 
-	userProfile, err := u.repo.FindByID(ctx, uuid.MustParse(user.Data.ID))
+	userID, err := uuid.Parse(user.Data.ID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errorhandler.NotFoundError{Message: "user profile not found"}
-		}
-		return nil, errorhandler.InternalServerError{Message: err.Error()}
+		return nil, errorhandler.InternalServerError{Message: "invalid user id from auth4me: " + err.Error()}
+	}
+
+	userProfile, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, dberror.Translate(err, "user profile")
 	}
 
 	userProfile.User = user.Data
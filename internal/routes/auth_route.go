@@ -4,30 +4,33 @@ import (
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
-	userprofileRepository "github.com/revandpratama/lognest/internal/modules/user-profile/repository"
 	"github.com/revandpratama/lognest/internal/modules/auth/handler"
 	"github.com/revandpratama/lognest/internal/modules/auth/repository"
 	"github.com/revandpratama/lognest/internal/modules/auth/usecase"
+	userprofileRepository "github.com/revandpratama/lognest/internal/modules/user-profile/repository"
 	"gorm.io/gorm"
 )
 
-func initAuthHandler(db *gorm.DB, httpClient *http.Client) handler.AuthHandler {
+func initAuthUsecase(db *gorm.DB, httpClient *http.Client) usecase.AuthUsecase {
 
 	userProfileRepo := userprofileRepository.NewUserProfileRepository(db)
 	authRepo := repository.NewAuthRepository(db)
 	authUsecase := usecase.NewAuthUsecase(authRepo, userProfileRepo, httpClient)
-	authHandler := handler.NewAuthHandler(authUsecase)
 
-	return authHandler
+	return authUsecase
 }
 
-func InitAuthRoute(api fiber.Router, db *gorm.DB, httpClient *http.Client) {
-	authHandler := initAuthHandler(db, httpClient)
+func InitAuthRoute(api fiber.Router, authUsecase usecase.AuthUsecase, authMiddleware fiber.Handler) {
+	authHandler := handler.NewAuthHandler(authUsecase)
 
 	auth := api.Group("/auth")
 
+	auth.Get("/csrf-token", authHandler.CSRFToken)
 	auth.Post("/login", authHandler.Login)
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/refresh-token", authHandler.RefreshToken)
 	auth.Post("/logout", authHandler.Logout)
+
+	auth.Get("/sessions", authMiddleware, authHandler.FindSessions)
+	auth.Post("/sessions/logout-others", withMFAPolicy("POST /auth/sessions/logout-others", authMiddleware, authHandler.LogoutOtherSessions)...)
 }
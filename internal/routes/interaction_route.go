@@ -2,7 +2,6 @@ package route
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/revandpratama/lognest/internal/middlewares"
 	"github.com/revandpratama/lognest/internal/modules/interaction/handler"
 	"github.com/revandpratama/lognest/internal/modules/interaction/repository"
 	"github.com/revandpratama/lognest/internal/modules/interaction/usecase"
@@ -16,18 +15,19 @@ func initInteractionHandler(db *gorm.DB) handler.InteractionHandler {
 	return interactionHandler
 }
 
-func InitInteractionRoutes(api fiber.Router, db *gorm.DB) {
+func InitInteractionRoutes(api fiber.Router, db *gorm.DB, authMiddleware fiber.Handler) {
 	interactionHandler := initInteractionHandler(db)
 
 	interaction := api.Group("/interactions")
 
-	interaction.Use(middlewares.AuthMiddleware())
+	interaction.Use(authMiddleware)
 
 	interaction.Post("/likes", interactionHandler.CreateLike)
-	interaction.Delete("/likes/:likeID", interactionHandler.DeleteLike)
+	interaction.Delete("/likes/:likeID", withMFAPolicy("DELETE /interactions/likes/:likeID", interactionHandler.DeleteLike)...)
 	interaction.Get("/likes/logs/:logID", interactionHandler.FindLikeByLogID)
 	interaction.Post("/comments", interactionHandler.CreateComment)
-	interaction.Put("/comments/:commentID", interactionHandler.UpdateComment)
+	interaction.Put("/comments/:id", interactionHandler.UpdateComment)
+	interaction.Patch("/comments/:id", interactionHandler.UpdateComment)
 	interaction.Get("/comments/log/:logID", interactionHandler.FindCommentByLogID)
-	interaction.Delete("/comments/:commentID", interactionHandler.DeleteComment)
+	interaction.Delete("/comments/:id", withMFAPolicy("DELETE /interactions/comments/:id", interactionHandler.DeleteComment)...)
 }
@@ -2,7 +2,6 @@ package route
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/revandpratama/lognest/internal/middlewares"
 	"github.com/revandpratama/lognest/internal/modules/log/handler"
 	"github.com/revandpratama/lognest/internal/modules/log/repository"
 	"github.com/revandpratama/lognest/internal/modules/log/usecase"
@@ -17,16 +16,17 @@ func InitLogHandlers(db *gorm.DB) handler.LogHandler {
 	return logHandler
 }
 
-func InitLogRoutes(api fiber.Router, db *gorm.DB) {
+func InitLogRoutes(api fiber.Router, db *gorm.DB, authMiddleware fiber.Handler) {
 	logHandler := InitLogHandlers(db)
 
 	log := api.Group("/logs")
 
-	log.Use(middlewares.AuthMiddleware())
+	log.Use(authMiddleware)
 
 	log.Get("/projects/:projectID", logHandler.FindByProjectID)
 	log.Get("/:id", logHandler.FindByID)
 	log.Post("/", logHandler.Create)
 	log.Put("/:id", logHandler.Update)
-	log.Delete("/:id", logHandler.Delete)
+	log.Patch("/:id", logHandler.Update)
+	log.Delete("/:id", withMFAPolicy("DELETE /logs/:id", logHandler.Delete)...)
 }
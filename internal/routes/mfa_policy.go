@@ -0,0 +1,42 @@
+package route
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/internal/middlewares"
+)
+
+// mfaPolicy records for each destructive operation whether it requires a session that completed
+// MFA. Every destructive route is registered through withMFAPolicy, so a new one cannot be added
+// without a decision here.
+var mfaPolicy = map[string]bool{
+	// Wide-reaching or irreversible.
+	"DELETE /projects/:id":              true, // takes the project and all of its logs down
+	"POST /auth/sessions/logout-others": true, // ends every other session of the user
+	"DELETE /storage/delete/:filePath":  true, // blobs are deleted for good
+
+	// A single item, which the owner can recreate.
+	"DELETE /logs/:id":                   false,
+	"DELETE /tags/:id":                   false,
+	"DELETE /interactions/comments/:id":  false,
+	"DELETE /interactions/likes/:likeID": false,
+}
+
+// withMFAPolicy returns the handlers of operation, with RequireMFA in front of the last one when
+// mfaPolicy asks for it. RequireMFA reads what AuthMiddleware sets, so the route must already be
+// authenticated by a group or an earlier handler. It panics for an operation missing from the
+// policy, which fails at startup rather than leaving a route unguarded.
+func withMFAPolicy(operation string, handlers ...fiber.Handler) []fiber.Handler {
+	requireMFA, ok := mfaPolicy[operation]
+	if !ok {
+		panic(fmt.Sprintf("no MFA policy for %q", operation))
+	}
+	if !requireMFA {
+		return handlers
+	}
+
+	last := len(handlers) - 1
+	guarded := append([]fiber.Handler{}, handlers[:last]...)
+	return append(guarded, middlewares.RequireMFA(), handlers[last])
+}
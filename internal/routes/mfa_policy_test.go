@@ -0,0 +1,107 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/token"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testApp registers every route on a database that never reaches a server.
+func testApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	app := fiber.New()
+	InitRoutes(app.Group("/api"), db, http.DefaultClient, nil)
+	return app
+}
+
+func TestEveryDeleteRouteHasMFAPolicy(t *testing.T) {
+	for _, route := range testApp(t).GetRoutes(true) {
+		if route.Method != fiber.MethodDelete {
+			continue
+		}
+		operation := route.Method + " " + strings.TrimPrefix(route.Path, "/api")
+		if _, ok := mfaPolicy[operation]; !ok {
+			t.Errorf("%s has no MFA policy", operation)
+		}
+	}
+}
+
+func TestMFARequiredOperation(t *testing.T) {
+	previousAlgorithm, previousSecret := config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET
+	config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET = "HS256", "test-secret"
+	t.Cleanup(func() {
+		config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET = previousAlgorithm, previousSecret
+	})
+
+	// Without a session ID the session check is skipped, so no database is needed.
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, token.CustomClaims{
+		UserID:           uuid.NewString(),
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	}).SignedString([]byte(config.ENV.JWT_SECRET))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	req := httptest.NewRequest(fiber.MethodDelete, "/api/projects/"+uuid.NewString(), nil)
+	req.Header.Set(fiber.HeaderCookie, token.AccessTokenCookieName+"=Bearer "+accessToken)
+
+	resp, err := testApp(t).Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusForbidden)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentType); got != errorhandler.ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, errorhandler.ProblemContentType)
+	}
+
+	var problem errorhandler.Problem
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Code != errorhandler.CodeMFARequired {
+		t.Errorf("code = %q, want %q", problem.Code, errorhandler.CodeMFARequired)
+	}
+}
+
+func TestWithMFAPolicy(t *testing.T) {
+	handler := func(c *fiber.Ctx) error { return nil }
+
+	if got := withMFAPolicy("DELETE /logs/:id", handler); len(got) != 1 {
+		t.Errorf("handlers for an operation without MFA = %d, want 1", len(got))
+	}
+	if got := withMFAPolicy("POST /auth/sessions/logout-others", handler, handler); len(got) != 3 {
+		t.Errorf("handlers for an operation with MFA = %d, want 3", len(got))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("withMFAPolicy accepted an operation missing from the policy")
+		}
+	}()
+	withMFAPolicy("DELETE /unknown", handler)
+}
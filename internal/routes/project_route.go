@@ -2,7 +2,6 @@ package route
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/revandpratama/lognest/internal/middlewares"
 	"github.com/revandpratama/lognest/internal/modules/project/handler"
 	"github.com/revandpratama/lognest/internal/modules/project/repository"
 	"github.com/revandpratama/lognest/internal/modules/project/usecase"
@@ -16,19 +15,20 @@ func initProjectHandler(db *gorm.DB) handler.ProjectHandler {
 	return projectHandler
 }
 
-func InitProjectRoutes(api fiber.Router, db *gorm.DB) {
+func InitProjectRoutes(api fiber.Router, db *gorm.DB, authMiddleware fiber.Handler) {
 	projectHandler := initProjectHandler(db)
 
 	projects := api.Group("/projects")
 
-	projects.Use(middlewares.AuthMiddleware())
+	projects.Use(authMiddleware)
 
 	projects.Get("/", projectHandler.FindAll)
+	projects.Get("/me", projectHandler.FindByUserID)
 	projects.Get("/:id", projectHandler.FindByID)
 	projects.Get("/users/:userID", projectHandler.FindByPublicUserID)
-	projects.Get("/me", projectHandler.FindByUserID)
 	projects.Get("/slug/:slug", projectHandler.FindBySlug)
 	projects.Post("/", projectHandler.Create)
 	projects.Put("/:id", projectHandler.Update)
-	projects.Delete("/:id", projectHandler.Delete)
+	projects.Patch("/:id", projectHandler.Update)
+	projects.Delete("/:id", withMFAPolicy("DELETE /projects/:id", projectHandler.Delete)...)
 }
@@ -5,22 +5,30 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/internal/middlewares"
 	"gorm.io/gorm"
 )
 
 func InitRoutes(api fiber.Router, db *gorm.DB, httpClient *http.Client, azureClient *azblob.Client) {
 
-	InitProjectRoutes(api, db)
+	authUsecase := initAuthUsecase(db, httpClient)
 
-	InitLogRoutes(api, db)
+	// * Shared so that token refreshes are de-duplicated across every route group
+	authMiddleware := middlewares.AuthMiddleware(authUsecase)
 
-	InitTagRoutes(api, db)
+	InitProjectRoutes(api, db, authMiddleware)
 
-	InitUserProfileRoutes(api, db, httpClient)
+	InitLogRoutes(api, db, authMiddleware)
 
-	InitInteractionRoutes(api, db)
+	InitTagRoutes(api, db, authMiddleware)
 
-	InitStorageRoute(api, azureClient)
+	InitUserProfileRoutes(api, db, httpClient, authMiddleware)
 
-	InitAuthRoute(api, db, httpClient)
+	InitInteractionRoutes(api, db, authMiddleware)
+
+	InitSearchRoutes(api, db, authMiddleware)
+
+	InitStorageRoute(api, azureClient, authMiddleware)
+
+	InitAuthRoute(api, authUsecase, authMiddleware)
 }
@@ -0,0 +1,27 @@
+package route
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/internal/modules/search/handler"
+	"github.com/revandpratama/lognest/internal/modules/search/repository"
+	"github.com/revandpratama/lognest/internal/modules/search/usecase"
+	"gorm.io/gorm"
+)
+
+func initSearchHandler(db *gorm.DB) handler.SearchHandler {
+	searchRepo := repository.NewSearchRepository(db)
+	searchUsecase := usecase.NewSearchUsecase(searchRepo)
+	searchHandler := handler.NewSearchHandler(searchUsecase)
+
+	return searchHandler
+}
+
+func InitSearchRoutes(api fiber.Router, db *gorm.DB, authMiddleware fiber.Handler) {
+	searchHandler := initSearchHandler(db)
+
+	search := api.Group("/search")
+
+	search.Use(authMiddleware)
+
+	search.Get("/", searchHandler.Search)
+}
@@ -14,12 +14,12 @@ func initStorageHandler(azureClient *azblob.Client) handler.StorageHandler {
 
 }
 
-func InitStorageRoute(api fiber.Router, azureClient *azblob.Client) {
+func InitStorageRoute(api fiber.Router, azureClient *azblob.Client, authMiddleware fiber.Handler) {
 
 	storageHandler := initStorageHandler(azureClient)
 
 	storage := api.Group("/storage")
 	storage.Get("/url/:filePath", storageHandler.GetURL)
 	storage.Post("/upload", storageHandler.Upload)
-	storage.Delete("/delete/:filePath", storageHandler.Delete)
+	storage.Delete("/delete/:filePath", withMFAPolicy("DELETE /storage/delete/:filePath", authMiddleware, storageHandler.Delete)...)
 }
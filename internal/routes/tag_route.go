@@ -2,7 +2,6 @@ package route
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/revandpratama/lognest/internal/middlewares"
 	"github.com/revandpratama/lognest/internal/modules/tag/handler"
 	"github.com/revandpratama/lognest/internal/modules/tag/repository"
 	"github.com/revandpratama/lognest/internal/modules/tag/usecase"
@@ -17,16 +16,17 @@ func initTagHandler(db *gorm.DB) handler.TagHandler {
 	return tagHandler
 }
 
-func InitTagRoutes(api fiber.Router, db *gorm.DB) {
+func InitTagRoutes(api fiber.Router, db *gorm.DB, authMiddleware fiber.Handler) {
 	tagHandler := initTagHandler(db)
 
 	tags := api.Group("/tags")
 
-	tags.Use(middlewares.AuthMiddleware())
+	tags.Use(authMiddleware)
 
 	tags.Get("/", tagHandler.FindAll)
 	tags.Get("/:id", tagHandler.FindByID)
 	tags.Post("/", tagHandler.Create)
 	tags.Put("/:id", tagHandler.Update)
-	tags.Delete("/:id", tagHandler.Delete)
+	tags.Patch("/:id", tagHandler.Update)
+	tags.Delete("/:id", withMFAPolicy("DELETE /tags/:id", tagHandler.Delete)...)
 }
@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/revandpratama/lognest/internal/middlewares"
 	"github.com/revandpratama/lognest/internal/modules/user-profile/handler"
 	"github.com/revandpratama/lognest/internal/modules/user-profile/repository"
 	"github.com/revandpratama/lognest/internal/modules/user-profile/usecase"
@@ -18,15 +17,16 @@ func initUserProfileHandler(db *gorm.DB, httpClient *http.Client) handler.UserPr
 	return userProfileHandler
 }
 
-func InitUserProfileRoutes(api fiber.Router, db *gorm.DB, httpClient *http.Client) {
+func InitUserProfileRoutes(api fiber.Router, db *gorm.DB, httpClient *http.Client, authMiddleware fiber.Handler) {
 	userProfileHandler := initUserProfileHandler(db, httpClient)
 
 	profiles := api.Group("/profiles")
 
-	profiles.Use(middlewares.AuthMiddleware())
+	profiles.Use(authMiddleware)
 
 	// profiles.Post("/", userProfileHandler.Create)
 	// profiles.Get("/:id", userProfileHandler.FindByID)
 	profiles.Get("/me", userProfileHandler.FindUser)
 	// profiles.Put("/:id", userProfileHandler.Update)
+	// profiles.Patch("/:id", userProfileHandler.Update)
 }
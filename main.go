@@ -5,13 +5,18 @@ import (
 	"math/rand"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
-	"github.com/revandpratama/lognest/cmd"
+	lognestCmd "github.com/revandpratama/lognest/cmd"
 	"github.com/revandpratama/lognest/config"
 	"github.com/revandpratama/lognest/internal/app"
+	"github.com/revandpratama/lognest/pkg/logging"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"gorm.io/gorm"
 )
 
 type Server struct {
@@ -32,6 +37,10 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to load config")
 	}
 
+	if err := logging.Setup(); err != nil {
+		log.Fatal().Err(err).Msg("failed to set up logging")
+	}
+
 	var rootCmd = &cobra.Command{
 		Use:   "app",
 		Short: "My app with subcommands",
@@ -44,38 +53,213 @@ func main() {
 	}
 
 	var fresh bool
+	var forceProduction bool
 	var migrateCmd = &cobra.Command{
 		Use:   "migrate",
 		Short: "Run database migrations",
 		Run: func(cmd *cobra.Command, args []string) {
 			log.Info().Msg("Running migrations...")
 
+			server := NewServer()
 			if fresh {
-				server := NewServer()
-				server.Migrate("fresh")
+				if err := lognestCmd.ConfirmDestructiveMigration("drop and re-create every lognest table", forceProduction, os.Stdin, os.Stdout); err != nil {
+					log.Fatal().Err(err).Msg("fresh migration refused")
+				}
+				server.Migrate(lognestCmd.MigrateDatabaseFresh)
 			} else {
-				server := NewServer()
-				server.Migrate("")
+				server.Migrate(lognestCmd.MigrateUp)
 			}
-
-			// run your migrations here
 		},
 	}
 	migrateCmd.Flags().BoolVarP(&fresh, "fresh", "f", false, "Run fresh migrations")
+	migrateCmd.PersistentFlags().BoolVar(&forceProduction, "force-production", false, "Allow destructive migrations when APP_ENV=production (still asks for confirmation)")
+
+	var migrateUpCmd = &cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			server := NewServer()
+			server.Migrate(lognestCmd.MigrateUp)
+		},
+	}
+
+	var migrateDownCmd = &cobra.Command{
+		Use:   "down [N]",
+		Short: "Roll back the last N migrations (default 1)",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			steps := 1
+			if len(args) == 1 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n <= 0 {
+					log.Fatal().Msgf("invalid number of migrations: %s", args[0])
+				}
+				steps = n
+			}
+
+			if err := lognestCmd.ConfirmDestructiveMigration(fmt.Sprintf("roll back %d migration(s)", steps), forceProduction, os.Stdin, os.Stdout); err != nil {
+				log.Fatal().Err(err).Msg("rollback refused")
+			}
+
+			server := NewServer()
+			server.Migrate(func(db *gorm.DB) error {
+				return lognestCmd.MigrateDown(db, steps)
+			})
+		},
+	}
+
+	var migrateStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show applied and pending migrations",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			server := NewServer()
+			server.Migrate(func(db *gorm.DB) error {
+				states, err := lognestCmd.MigrationStatus(db)
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+				for _, state := range states {
+					status, appliedAt := "pending", "-"
+					if state.AppliedAt != nil {
+						status, appliedAt = "applied", state.AppliedAt.Format(time.RFC3339)
+						if state.Modified {
+							status = "applied (modified)"
+						}
+					}
+					fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", state.Version, state.Name, status, appliedAt)
+				}
+				return w.Flush()
+			})
+		},
+	}
+
+	var migrateCreateCmd = &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a new empty up/down migration pair",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			paths, err := lognestCmd.CreateMigration(args[0])
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to create migration")
+			}
+			for _, path := range paths {
+				log.Info().Msgf("created %s", path)
+			}
+		},
+	}
+
+	var migrateCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Compare the live schema with the entity models and exit non-zero on drift",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			server := NewServer()
+			server.Migrate(func(db *gorm.DB) error {
+				drifts, err := lognestCmd.CheckSchemaDrift(db)
+				if err != nil {
+					return err
+				}
+
+				if len(drifts) == 0 {
+					fmt.Println("no schema drift detected")
+					return nil
+				}
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "TABLE\tKIND\tDETAIL")
+				for _, drift := range drifts {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", drift.Table, drift.Kind, drift.Detail)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+
+				return fmt.Errorf("schema drift detected: %d issue(s)", len(drifts))
+			})
+		},
+	}
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd, migrateCreateCmd, migrateCheckCmd)
 
+	var generateDryRun bool
 	var generateCmd = &cobra.Command{
-		Use:   "generate",
-		Short: "Generate modules",
+		Use:   "generate <module> [name:type[:modifier...]...]",
+		Short: "Generate a CRUD module and wire its routes, model and migration",
+		Long: `Generate a CRUD module across entity, dto, repository, usecase and handler,
+register its routes and model, and add a migration for its table.
+
+Field types: string, text, int, float, bool, uuid, time.
+Field modifiers: required, unique, index.
+
+Example:
+  lognest generate article title:string:required slug:string:unique body:text`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			server := NewServer()
+			server.GenerateModule(lognestCmd.GenerateOptions{
+				ModuleName: args[0],
+				Fields:     args[1:],
+				DryRun:     generateDryRun,
+			})
+		},
+	}
+	var endpointMethod, endpointPath, endpointEntity string
+	var generateEndpointCmd = &cobra.Command{
+		Use:   "endpoint <module> <Name>",
+		Short: "Add an endpoint to an existing module across repository, usecase, handler and routes",
+		Long: `Add an endpoint to an existing module. The interface method and an implementation
+are inserted into the repository, usecase and handler layers and the route is
+registered. The usecase method is a stub answering 501 Not Implemented until it is
+filled in, and POST, PUT and PATCH endpoints bind a new request DTO in the module's
+dto package. Existing methods, DTOs and routes are never overwritten.
+
+Example:
+  lognest generate endpoint project FindBySlug --method GET --path /slug/:slug`,
+		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
-			log.Info().Msg("Generating modules...")
+			server := NewServer()
+			server.GenerateEndpoint(lognestCmd.EndpointOptions{
+				ModuleName: args[0],
+				Name:       args[1],
+				Method:     endpointMethod,
+				Path:       endpointPath,
+				Entity:     endpointEntity,
+				DryRun:     generateDryRun,
+			})
+		},
+	}
+	generateEndpointCmd.Flags().StringVar(&endpointMethod, "method", "GET", "HTTP method of the endpoint: GET, POST, PUT, PATCH or DELETE")
+	generateEndpointCmd.Flags().StringVar(&endpointPath, "path", "", "Route path relative to the module group, e.g. /slug/:slug")
+	generateEndpointCmd.Flags().StringVar(&endpointEntity, "entity", "", "Entity the endpoint works on; defaults to the module's primary entity")
+	_ = generateEndpointCmd.MarkFlagRequired("path")
+
+	generateCmd.AddCommand(generateEndpointCmd)
+	generateCmd.PersistentFlags().BoolVar(&generateDryRun, "dry-run", false, "Print the diff of every file that would change without writing anything")
+
+	var seedSize string
+	var seedValue int64
+	var seedReset bool
+	var seedCmd = &cobra.Command{
+		Use:   "seed",
+		Short: "Seed the database with deterministic fixture data",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log.Info().Msg("Seeding database...")
 
 			server := NewServer()
-			server.GenerateModule(args[0])
-			// run your migrations here
+			server.Seed(lognestCmd.SeedOptions{Size: seedSize, Seed: seedValue, Reset: seedReset})
 		},
 	}
+	seedCmd.Flags().StringVarP(&seedSize, "size", "s", "small", "Dataset size preset: small, medium or large")
+	seedCmd.Flags().Int64Var(&seedValue, "seed", 1, "Seed for the pseudo-random generator; the same seed yields the same data")
+	seedCmd.Flags().BoolVar(&seedReset, "reset", false, "Truncate the seeded tables, and every table referencing them, before seeding")
 
-	rootCmd.AddCommand(migrateCmd, generateCmd)
+	rootCmd.AddCommand(migrateCmd, generateCmd, seedCmd)
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -88,8 +272,10 @@ func (s *Server) Start() {
 	signal.Notify(s.shutdownCh, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
 	apps, err := app.NewApp(
+		app.WithTracing(),
 		app.WithDB(),
 		app.WithAzureBlobStorage(),
+		app.WithTokenVerifier(),
 		app.WithRESTServer(),
 	)
 	if err != nil {
@@ -128,7 +314,7 @@ func randomGoodbye() string {
 	return byes[rand.Intn(len(byes))]
 }
 
-func (s *Server) Migrate(flag string) {
+func (s *Server) Migrate(run func(db *gorm.DB) error) {
 	apps, err := app.NewApp(
 		app.WithDB(),
 	)
@@ -137,29 +323,49 @@ func (s *Server) Migrate(flag string) {
 		log.Fatal().Err(err).Msg("failed to create app")
 	}
 
-	if err := cmd.EnsureSchema(apps.DB, config.ENV.LOGNEST_SCHEMA); err != nil {
+	if err := lognestCmd.EnsureSchema(apps.DB, config.ENV.LOGNEST_SCHEMA); err != nil {
 		log.Fatal().Err(err).Msg("failed to ensure schema")
 	}
 
-	if flag == "fresh" {
-		if err := cmd.MigrateDatabaseFresh(apps.DB); err != nil {
-			log.Fatal().Err(err).Msg("failed to migrate database fresh")
-		}
+	if err := run(apps.DB); err != nil {
+		log.Fatal().Err(err).Msg("failed to migrate database")
 	}
 
-	if flag == "" {
-		if err := cmd.MigrateDatabase(apps.DB); err != nil {
-			log.Fatal().Err(err).Msg("failed to migrate database")
-		}
+	log.Info().Msg("migration command completed successfully")
+
+	if err := apps.Stop(); err != nil {
+		log.Error().Err(err).Msgf("failed to stop app cleanly, cause: %v", err)
+	}
+}
+
+func (s *Server) Seed(opts lognestCmd.SeedOptions) {
+	apps, err := app.NewApp(
+		app.WithDB(),
+	)
+
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create app")
+	}
+
+	if err := lognestCmd.SeedDatabase(apps.DB, opts); err != nil {
+		log.Fatal().Err(err).Msg("failed to seed database")
 	}
 
-	log.Info().Msg("database migrated successfully")
+	log.Info().Msg("database seeded successfully")
 
 	if err := apps.Stop(); err != nil {
 		log.Error().Err(err).Msgf("failed to stop app cleanly, cause: %v", err)
 	}
 }
 
-func (s *Server) GenerateModule(moduleName string) {
-	cmd.GenerateModule(moduleName)
+func (s *Server) GenerateEndpoint(opts lognestCmd.EndpointOptions) {
+	if err := lognestCmd.GenerateEndpoint(opts); err != nil {
+		log.Fatal().Err(err).Msg("failed to generate endpoint")
+	}
+}
+
+func (s *Server) GenerateModule(opts lognestCmd.GenerateOptions) {
+	if err := lognestCmd.GenerateModule(opts); err != nil {
+		log.Fatal().Err(err).Msg("failed to generate module")
+	}
 }
@@ -5,7 +5,6 @@ import (
 	"crypto/rand"
 	"fmt"
 	"io"
-	"log"
 	"math/big"
 	"path/filepath"
 	"regexp"
@@ -16,6 +15,11 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
 	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/logging"
+	"github.com/revandpratama/lognest/pkg/metrics"
+	"github.com/revandpratama/lognest/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Pre-compile regex patterns for efficiency.
@@ -76,7 +80,8 @@ func SanitizeFileName(fileName string) string {
 }
 
 func UploadFile(ctx context.Context, azblobClient *azblob.Client, containerName string, pathName string, fileName string, file io.Reader) (string, error) {
-	log.Printf("Azure client USED in Usecase. Memory Address: %p", azblobClient)
+	logging.Ctx(ctx).Debug().Str("container", containerName).Str("path", pathName).Str("file", fileName).Msg("uploading blob")
+
 	containerClient := azblobClient.ServiceClient().NewContainerClient(containerName)
 
 	fileFullPath := fmt.Sprintf("%s/%s", pathName, fileName)
@@ -84,15 +89,25 @@ func UploadFile(ctx context.Context, azblobClient *azblob.Client, containerName
 
 	blobClient := containerClient.NewBlockBlobClient(fileFullPath)
 
-	_, err := blobClient.UploadStream(ctx, file, nil)
+	ctx, span := startSpan(ctx, "azblob.UploadFile", containerName, fileFullPath)
+
+	counted := &countingReader{reader: file}
+	_, err := blobClient.UploadStream(ctx, counted, nil)
+	span.SetAttributes(attribute.Int64("blob.size", counted.bytes))
+	tracing.End(span, err)
+	metrics.StorageUploads.WithLabelValues(metrics.Status(err)).Inc()
 	if err != nil {
 		return "", err
 	}
+	metrics.StorageUploadBytes.Add(float64(counted.bytes))
 
 	return blobClient.URL(), nil
 }
 
-func GetFileURL(ctx context.Context, azblobClient *azblob.Client, containerName string, filePath string) (string, error) {
+func GetFileURL(ctx context.Context, azblobClient *azblob.Client, containerName string, filePath string) (_ string, err error) {
+	// Signing is local, but the span shows how many URLs a page signs.
+	_, span := startSpan(ctx, "azblob.GetFileURL", containerName, filePath)
+	defer func() { tracing.End(span, err) }()
 
 	permissions := sas.BlobPermissions{
 		Read: true,
@@ -120,10 +135,32 @@ func DeleteFile(ctx context.Context, azblobClient *azblob.Client, containerName
 	containerClient := azblobClient.ServiceClient().NewContainerClient(containerName)
 	blobClient := containerClient.NewBlockBlobClient(filePath)
 
+	ctx, span := startSpan(ctx, "azblob.DeleteFile", containerName, filePath)
+
 	_, err := blobClient.Delete(ctx, nil)
+	tracing.End(span, err)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func startSpan(ctx context.Context, name string, containerName string, blobPath string) (context.Context, trace.Span) {
+	return tracing.Tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("blob.container", containerName), attribute.String("blob.path", blobPath)),
+	)
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	reader io.Reader
+	bytes  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.bytes += int64(n)
+	return n, err
+}
@@ -0,0 +1,57 @@
+package csrf
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/config"
+)
+
+const (
+	CookieName = "csrf_token"
+	HeaderName = "X-CSRF-Token"
+
+	CookieTTL = 24 * time.Hour
+
+	tokenLength = 32
+)
+
+// Generate returns a new random, URL-safe CSRF token.
+func Generate() (string, error) {
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// SetCookie stores the token in an HTTP-only cookie. The frontend never reads this cookie;
+// it echoes the token it received from the issuing endpoint in the X-CSRF-Token header.
+func SetCookie(c *fiber.Ctx, token string) {
+	cookie := &fiber.Cookie{
+		Name:     CookieName,
+		Value:    token,
+		Expires:  time.Now().Add(CookieTTL),
+		HTTPOnly: true,
+		Path:     "/",
+	}
+
+	if config.ENV.APP_ENV == "production" {
+		cookie.SameSite = "None"
+		cookie.Domain = ".revandpratama.com"
+		cookie.Secure = true
+	}
+
+	c.Cookie(cookie)
+}
+
+// Verify reports whether the submitted header token matches the cookie token.
+func Verify(cookieToken string, headerToken string) bool {
+	if cookieToken == "" || headerToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookieToken), []byte(headerToken)) == 1
+}
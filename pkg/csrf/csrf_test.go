@@ -0,0 +1,103 @@
+package csrf
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/config"
+)
+
+func TestGenerate(t *testing.T) {
+	first, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	second, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if first == second {
+		t.Error("Generate returned the same token twice")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(first)
+	if err != nil {
+		t.Fatalf("token %q is not URL-safe base64: %v", first, err)
+	}
+	if len(raw) != tokenLength {
+		t.Errorf("token carries %d bytes, want %d", len(raw), tokenLength)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		want   bool
+	}{
+		{name: "matching", cookie: "abc", header: "abc", want: true},
+		{name: "different", cookie: "abc", header: "abd", want: false},
+		{name: "prefix", cookie: "abc", header: "ab", want: false},
+		{name: "no header", cookie: "abc", header: "", want: false},
+		{name: "no cookie", cookie: "", header: "abc", want: false},
+		{name: "neither", cookie: "", header: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.cookie, tt.header); got != tt.want {
+				t.Errorf("Verify(%q, %q) = %v, want %v", tt.cookie, tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetCookie(t *testing.T) {
+	tests := []struct {
+		env        string
+		wantDomain string
+		wantSecure bool
+	}{
+		{env: "development"},
+		{env: "production", wantDomain: ".revandpratama.com", wantSecure: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			previous := config.ENV.APP_ENV
+			config.ENV.APP_ENV = tt.env
+			t.Cleanup(func() { config.ENV.APP_ENV = previous })
+
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				SetCookie(c, "token")
+				return nil
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+
+			cookies := resp.Cookies()
+			if len(cookies) != 1 {
+				t.Fatalf("got %d cookies, want 1", len(cookies))
+			}
+			cookie := cookies[0]
+			if cookie.Name != CookieName || cookie.Value != "token" {
+				t.Errorf("cookie = %s=%s, want %s=token", cookie.Name, cookie.Value, CookieName)
+			}
+			if !cookie.HttpOnly {
+				t.Error("cookie is readable from scripts")
+			}
+			if cookie.Path != "/" {
+				t.Errorf("Path = %q, want /", cookie.Path)
+			}
+			if cookie.Domain != tt.wantDomain || cookie.Secure != tt.wantSecure {
+				t.Errorf("Domain, Secure = %q, %v, want %q, %v", cookie.Domain, cookie.Secure, tt.wantDomain, tt.wantSecure)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package dberror
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"gorm.io/gorm"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html.
+const (
+	notNullViolation     = "23502"
+	foreignKeyViolation  = "23503"
+	uniqueViolation      = "23505"
+	checkViolation       = "23514"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// keyColumns extracts the columns from details such as `Key (slug)=(my-project) already exists.`
+var keyColumns = regexp.MustCompile(`^Key \(([^)]+)\)`)
+
+// Translate maps an error from a repository to an errorhandler error, so that every module reports
+// missing rows, constraint violations and conflicting transactions the same way. resource names the
+// row in messages, e.g. "project". Errors that already carry a status are returned unchanged and
+// anything unrecognised becomes an internal error.
+func Translate(err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+
+	if errorhandler.From(err).Code != errorhandler.CodeInternal {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errorhandler.NotFoundError{Message: resource + " not found"}
+	}
+
+	if errors.Is(err, etag.ErrVersionMismatch) {
+		return errorhandler.PreconditionFailedError{Message: resource + " was modified by another request"}
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return errorhandler.InternalServerError{Message: err.Error()}
+	}
+
+	switch pgErr.Code {
+	case uniqueViolation:
+		if columns := columnsOf(pgErr); columns != "" {
+			return errorhandler.ConflictError{Message: fmt.Sprintf("%s with this %s already exists", resource, columns)}
+		}
+		return errorhandler.ConflictError{Message: resource + " already exists"}
+	case foreignKeyViolation:
+		// The same code covers deletes of rows that are still referenced and writes that reference
+		// a missing row; only the detail tells them apart.
+		if strings.Contains(pgErr.Detail, "is still referenced") {
+			return errorhandler.ConflictError{Message: resource + " is still in use"}
+		}
+		if columns := columnsOf(pgErr); columns != "" {
+			return errorhandler.BadRequestError{Message: columns + " refers to a record that does not exist"}
+		}
+		return errorhandler.BadRequestError{Message: resource + " refers to a record that does not exist"}
+	case checkViolation:
+		return errorhandler.BadRequestError{Message: fmt.Sprintf("%s violates %s", resource, pgErr.ConstraintName)}
+	case notNullViolation:
+		return errorhandler.BadRequestError{Message: pgErr.ColumnName + " is required"}
+	case serializationFailure, deadlockDetected:
+		return errorhandler.ConflictError{Message: resource + " was changed by a concurrent request, please retry"}
+	}
+
+	return errorhandler.InternalServerError{Message: err.Error()}
+}
+
+func columnsOf(pgErr *pgconn.PgError) string {
+	match := keyColumns.FindStringSubmatch(pgErr.Detail)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
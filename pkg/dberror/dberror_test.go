@@ -0,0 +1,107 @@
+package dberror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"github.com/revandpratama/lognest/pkg/etag"
+	"gorm.io/gorm"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "unique violation",
+			err:         &pgconn.PgError{Code: uniqueViolation, Detail: "Key (slug)=(my-project) already exists."},
+			wantStatus:  fiber.StatusConflict,
+			wantMessage: "project with this slug already exists",
+		},
+		{
+			name:        "unique violation without detail",
+			err:         &pgconn.PgError{Code: uniqueViolation},
+			wantStatus:  fiber.StatusConflict,
+			wantMessage: "project already exists",
+		},
+		{
+			name:        "delete of a referenced row",
+			err:         &pgconn.PgError{Code: foreignKeyViolation, Detail: `Key (id)=(1) is still referenced from table "logs".`},
+			wantStatus:  fiber.StatusConflict,
+			wantMessage: "project is still in use",
+		},
+		{
+			name:        "reference to a missing row",
+			err:         &pgconn.PgError{Code: foreignKeyViolation, Detail: `Key (user_profile_id)=(1) is not present in table "user_profiles".`},
+			wantStatus:  fiber.StatusBadRequest,
+			wantMessage: "user_profile_id refers to a record that does not exist",
+		},
+		{
+			name:        "check violation",
+			err:         &pgconn.PgError{Code: checkViolation, ConstraintName: "projects_title_check"},
+			wantStatus:  fiber.StatusBadRequest,
+			wantMessage: "project violates projects_title_check",
+		},
+		{
+			name:        "not null violation",
+			err:         &pgconn.PgError{Code: notNullViolation, ColumnName: "title"},
+			wantStatus:  fiber.StatusBadRequest,
+			wantMessage: "title is required",
+		},
+		{
+			name:        "serialization failure",
+			err:         &pgconn.PgError{Code: serializationFailure},
+			wantStatus:  fiber.StatusConflict,
+			wantMessage: "project was changed by a concurrent request, please retry",
+		},
+		{
+			name:        "deadlock",
+			err:         &pgconn.PgError{Code: deadlockDetected},
+			wantStatus:  fiber.StatusConflict,
+			wantMessage: "project was changed by a concurrent request, please retry",
+		},
+		{
+			name:        "wrapped",
+			err:         fmt.Errorf("create project: %w", &pgconn.PgError{Code: notNullViolation, ColumnName: "slug"}),
+			wantStatus:  fiber.StatusBadRequest,
+			wantMessage: "slug is required",
+		},
+		{name: "record not found", err: gorm.ErrRecordNotFound, wantStatus: fiber.StatusNotFound, wantMessage: "project not found"},
+		{
+			name:        "version mismatch",
+			err:         etag.ErrVersionMismatch,
+			wantStatus:  fiber.StatusPreconditionFailed,
+			wantMessage: "project was modified by another request",
+		},
+		{
+			name:        "already translated",
+			err:         errorhandler.ForbiddenError{Message: "not your project"},
+			wantStatus:  fiber.StatusForbidden,
+			wantMessage: "not your project",
+		},
+		{name: "other postgres error", err: &pgconn.PgError{Code: "42P01"}, wantStatus: fiber.StatusInternalServerError},
+		{name: "other error", err: errors.New("connection refused"), wantStatus: fiber.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := errorhandler.From(Translate(tt.err, "project"))
+			if apiErr.Status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", apiErr.Status, tt.wantStatus)
+			}
+			if tt.wantMessage != "" && apiErr.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+		})
+	}
+
+	if err := Translate(nil, "project"); err != nil {
+		t.Errorf("Translate(nil) = %v, want nil", err)
+	}
+}
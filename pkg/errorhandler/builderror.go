@@ -1,37 +1,152 @@
 package errorhandler
 
-import "github.com/gofiber/fiber/v2"
-
-func BuildError(c *fiber.Ctx, err error, errors []string) error {
-	var statusCode int
-	var response ErrorResponse
-
-	switch err.(type) {
-	case NotFoundError:
-		statusCode = fiber.StatusNotFound
-	case BadRequestError:
-		statusCode = fiber.StatusBadRequest
-	case UnauthorizedError:
-		statusCode = fiber.StatusUnauthorized
-	case InternalServerError:
-		statusCode = fiber.StatusInternalServerError
-	case ConflictError:
-		statusCode = fiber.StatusConflict
-	default:
-		statusCode = fiber.StatusInternalServerError
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/logging"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details document, extended with the error code, the per-field
+// errors and the request ID.
+type Problem struct {
+	Type      string   `json:"type"`
+	Title     string   `json:"title"`
+	Status    int      `json:"status"`
+	Detail    string   `json:"detail,omitempty"`
+	Instance  string   `json:"instance,omitempty"`
+	Code      string   `json:"code"`
+	Errors    []string `json:"errors,omitempty"`
+	RequestID string   `json:"request_id,omitempty"`
+}
+
+// BuildError renders err as problem details. fields are per-field validation messages; a bad
+// request that carries them is reported as validation_failed.
+func BuildError(c *fiber.Ctx, err error, fields []string) error {
+	apiErr := From(err)
+
+	if len(fields) > 0 {
+		apiErr.Fields = fields
+		if apiErr.Code == CodeBadRequest {
+			apiErr.Code = CodeValidationFailed
+		}
 	}
 
-	response = ErrorResponse{
-		Status:  "error",
-		Message: err.Error(),
-		Errors:  errors,
+	requestID := RequestID(c)
+
+	if apiErr.Status >= fiber.StatusInternalServerError {
+		// The request logger on the user context carries the request ID, method and route.
+		logging.Ctx(c.UserContext()).Error().
+			Err(apiErr.Cause).
+			Str("code", apiErr.Code).
+			Msg(apiErr.Message)
+	}
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(apiErr.Status),
+		Status:    apiErr.Status,
+		Detail:    apiErr.Message,
+		Instance:  c.OriginalURL(),
+		Code:      apiErr.Code,
+		Errors:    apiErr.Fields,
+		RequestID: requestID,
 	}
 
-	return c.Status(statusCode).JSON(response)
+	return c.Status(apiErr.Status).JSON(problem, ProblemContentType)
 }
 
-type ErrorResponse struct {
-	Status  string   `json:"status"`
-	Message string   `json:"message"`
-	Errors  []string `json:"errors,omitempty"`
+// From returns the API error in err's chain. The error types above are mapped to their status and
+// code, so they keep them when wrapped with %w; anything else is an internal error whose message is
+// not shown to users.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		// Copy, so that BuildError does not modify a shared error value.
+		copied := *apiErr
+		return &copied
+	}
+
+	var (
+		notFound             NotFoundError
+		badRequest           BadRequestError
+		unauthorized         UnauthorizedError
+		forbidden            ForbiddenError
+		mfaRequired          MFARequiredError
+		conflict             ConflictError
+		preconditionFailed   PreconditionFailedError
+		preconditionRequired PreconditionRequiredError
+		notImplemented       NotImplementedError
+	)
+
+	var fiberErr *fiber.Error
+
+	switch {
+	case errors.As(err, &fiberErr) && fiberErr.Code < fiber.StatusInternalServerError:
+		// Raised by Fiber itself, e.g. for unknown routes or oversized bodies.
+		return Wrap(err, fiberErr.Code, codeForStatus(fiberErr.Code), fiberErr.Message)
+	case errors.As(err, &notFound):
+		return Wrap(err, fiber.StatusNotFound, CodeNotFound, notFound.Message)
+	case errors.As(err, &badRequest):
+		return Wrap(err, fiber.StatusBadRequest, CodeBadRequest, badRequest.Message)
+	case errors.As(err, &unauthorized):
+		return Wrap(err, fiber.StatusUnauthorized, CodeUnauthorized, unauthorized.Message)
+	case errors.As(err, &mfaRequired):
+		return Wrap(err, fiber.StatusForbidden, CodeMFARequired, mfaRequired.Message)
+	case errors.As(err, &forbidden):
+		return Wrap(err, fiber.StatusForbidden, CodeForbidden, forbidden.Message)
+	case errors.As(err, &conflict):
+		return Wrap(err, fiber.StatusConflict, CodeConflict, conflict.Message)
+	case errors.As(err, &preconditionFailed):
+		return Wrap(err, fiber.StatusPreconditionFailed, CodePreconditionFailed, preconditionFailed.Message)
+	case errors.As(err, &preconditionRequired):
+		return Wrap(err, fiber.StatusPreconditionRequired, CodePreconditionRequired, preconditionRequired.Message)
+	case errors.As(err, &notImplemented):
+		return Wrap(err, fiber.StatusNotImplemented, CodeNotImplemented, notImplemented.Message)
+	}
+
+	// InternalServerError messages usually carry the underlying error, so they are only logged.
+	return Wrap(err, fiber.StatusInternalServerError, CodeInternal, "internal server error")
+}
+
+// Handler is the Fiber ErrorHandler. It renders errors that handlers return instead of passing to
+// BuildError, Fiber's own errors and recovered panics as problem details.
+func Handler(c *fiber.Ctx, err error) error {
+	return BuildError(c, err, nil)
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case fiber.StatusUnauthorized:
+		return CodeUnauthorized
+	case fiber.StatusForbidden:
+		return CodeForbidden
+	case fiber.StatusNotFound:
+		return CodeNotFound
+	case fiber.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case fiber.StatusConflict:
+		return CodeConflict
+	case fiber.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case fiber.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case fiber.StatusPreconditionRequired:
+		return CodePreconditionRequired
+	case fiber.StatusTooManyRequests:
+		return CodeTooManyRequests
+	}
+	return CodeBadRequest
+}
+
+// RequestID returns the ID of the request, as set on the response or sent by the client.
+func RequestID(c *fiber.Ctx) string {
+	if requestID := c.GetRespHeader(fiber.HeaderXRequestID); requestID != "" {
+		return requestID
+	}
+	return c.Get(fiber.HeaderXRequestID)
 }
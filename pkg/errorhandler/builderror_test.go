@@ -0,0 +1,89 @@
+package errorhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "not found", err: NotFoundError{Message: "m"}, wantStatus: fiber.StatusNotFound, wantCode: CodeNotFound},
+		{name: "bad request", err: BadRequestError{Message: "m"}, wantStatus: fiber.StatusBadRequest, wantCode: CodeBadRequest},
+		{name: "unauthorized", err: UnauthorizedError{Message: "m"}, wantStatus: fiber.StatusUnauthorized, wantCode: CodeUnauthorized},
+		{name: "forbidden", err: ForbiddenError{Message: "m"}, wantStatus: fiber.StatusForbidden, wantCode: CodeForbidden},
+		{name: "mfa required", err: MFARequiredError{Message: "m"}, wantStatus: fiber.StatusForbidden, wantCode: CodeMFARequired},
+		{name: "conflict", err: ConflictError{Message: "m"}, wantStatus: fiber.StatusConflict, wantCode: CodeConflict},
+		{name: "precondition failed", err: PreconditionFailedError{Message: "m"}, wantStatus: fiber.StatusPreconditionFailed, wantCode: CodePreconditionFailed},
+		{name: "precondition required", err: PreconditionRequiredError{Message: "m"}, wantStatus: fiber.StatusPreconditionRequired, wantCode: CodePreconditionRequired},
+		{name: "not implemented", err: NotImplementedError{Message: "m"}, wantStatus: fiber.StatusNotImplemented, wantCode: CodeNotImplemented},
+		{name: "api error", err: New(fiber.StatusTooManyRequests, CodeTooManyRequests, "m"), wantStatus: fiber.StatusTooManyRequests, wantCode: CodeTooManyRequests},
+		{name: "fiber error", err: fiber.ErrMethodNotAllowed, wantStatus: fiber.StatusMethodNotAllowed, wantCode: CodeMethodNotAllowed},
+		{name: "fiber server error", err: fiber.ErrServiceUnavailable, wantStatus: fiber.StatusInternalServerError, wantCode: CodeInternal},
+		{name: "internal server error", err: InternalServerError{Message: "m"}, wantStatus: fiber.StatusInternalServerError, wantCode: CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wrapping must not lose the status of the error inside.
+			for _, err := range []error{tt.err, fmt.Errorf("context: %w", tt.err)} {
+				apiErr := From(err)
+				if apiErr.Status != tt.wantStatus || apiErr.Code != tt.wantCode {
+					t.Errorf("From(%v) = %d %s, want %d %s", err, apiErr.Status, apiErr.Code, tt.wantStatus, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestFromCopiesAPIErrors(t *testing.T) {
+	shared := New(fiber.StatusConflict, CodeConflict, "taken")
+
+	From(shared).Fields = []string{"slug"}
+	if shared.Fields != nil {
+		t.Error("From returned the shared error instead of a copy")
+	}
+}
+
+func TestBuildErrorHidesInternalMessages(t *testing.T) {
+	for _, err := range []error{
+		errors.New(`pq: relation "secret_table" does not exist`),
+		InternalServerError{Message: `pq: relation "secret_table" does not exist`},
+	} {
+		app := fiber.New()
+		app.Get("/", func(c *fiber.Ctx) error {
+			return BuildError(c, err, nil)
+		})
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusInternalServerError {
+			t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusInternalServerError)
+		}
+		if got := resp.Header.Get(fiber.HeaderContentType); got != ProblemContentType {
+			t.Errorf("Content-Type = %q, want %q", got, ProblemContentType)
+		}
+
+		var problem Problem
+		if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+			t.Fatalf("decode problem: %v", err)
+		}
+		if problem.Code != CodeInternal {
+			t.Errorf("code = %q, want %q", problem.Code, CodeInternal)
+		}
+		if strings.Contains(problem.Title+problem.Detail, "secret_table") {
+			t.Errorf("problem %+v leaks the internal message", problem)
+		}
+	}
+}
@@ -18,6 +18,30 @@ type ConflictError struct {
 	Message string `json:"message"`
 }
 
+type ForbiddenError struct {
+	Message string `json:"message"`
+}
+
+// MFARequiredError signals that the session must complete an MFA step-up before retrying.
+type MFARequiredError struct {
+	Message string `json:"message"`
+}
+
+// PreconditionFailedError signals that the If-Match version no longer matches the resource.
+type PreconditionFailedError struct {
+	Message string `json:"message"`
+}
+
+// PreconditionRequiredError signals that a write was sent without the required If-Match header.
+type PreconditionRequiredError struct {
+	Message string `json:"message"`
+}
+
+// NotImplementedError is returned by generated endpoints until they are filled in.
+type NotImplementedError struct {
+	Message string `json:"message"`
+}
+
 func (e NotFoundError) Error() string {
 	return e.Message
 }
@@ -34,3 +58,72 @@ func (e InternalServerError) Error() string {
 func (e ConflictError) Error() string {
 	return e.Message
 }
+
+func (e ForbiddenError) Error() string {
+	return e.Message
+}
+
+func (e MFARequiredError) Error() string {
+	return e.Message
+}
+
+func (e PreconditionFailedError) Error() string {
+	return e.Message
+}
+
+func (e PreconditionRequiredError) Error() string {
+	return e.Message
+}
+
+func (e NotImplementedError) Error() string {
+	return e.Message
+}
+
+// Stable, machine-readable error codes. Clients branch on these rather than on messages.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeMFARequired          = "mfa_required"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeConflict             = "conflict"
+	CodePreconditionFailed   = "precondition_failed"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodePreconditionRequired = "precondition_required"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeInternal             = "internal_error"
+	CodeNotImplemented       = "not_implemented"
+)
+
+// Error is an API error: the HTTP status and stable code clients branch on, the message shown to
+// users, the per-field errors, and the cause, which is logged but never returned.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Fields  []string
+	Cause   error
+}
+
+// New returns an error with the given status, code and user message.
+func New(status int, code string, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Wrap returns an error with the given status, code and user message, caused by err.
+func Wrap(err error, status int, code string, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message, Cause: err}
+}
+
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Cause.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
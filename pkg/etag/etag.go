@@ -0,0 +1,98 @@
+package etag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+)
+
+// ErrVersionMismatch is returned by repositories when the row exists but its version is not the
+// one the write was conditioned on.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// Format renders the strong entity tag of a representation of a row at version, e.g.
+// "3-9b2c1f0e5a7d4c38". The suffix is a digest of the representation, so the tag also changes with
+// the rows embedded in it; If-Match only compares the version.
+func Format(version int64, representation any) string {
+	return `"` + strconv.FormatInt(version, 10) + "-" + digest(representation) + `"`
+}
+
+// Set adds the entity tag of a representation of a row at version to the response.
+func Set(c *fiber.Ctx, version int64, representation any) {
+	c.Set(fiber.HeaderETag, Format(version, representation))
+}
+
+// Weak renders a weak entity tag for a representation that has no version of its own, such as a
+// page of a list.
+func Weak(representation any) string {
+	return `W/"` + digest(representation) + `"`
+}
+
+// SetWeak adds the weak entity tag of representation to the response.
+func SetWeak(c *fiber.Ctx, representation any) {
+	c.Set(fiber.HeaderETag, Weak(representation))
+}
+
+func digest(representation any) string {
+	hash := fnv.New64a()
+	if err := json.NewEncoder(hash).Encode(representation); err != nil {
+		// Response DTOs always encode; fall back to the tag of an empty digest rather than failing.
+		return ""
+	}
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// IfMatch returns the version the request is conditioned on, or nil when any version may be
+// replaced: for "If-Match: *" and, unless REQUIRE_IF_MATCH is enabled, without the header. Both
+// tags from Format and bare versions such as "3" are accepted.
+func IfMatch(c *fiber.Ctx) (*int64, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+
+	switch {
+	case header == "":
+		if !required() {
+			return nil, nil
+		}
+		return nil, errorhandler.PreconditionRequiredError{Message: "If-Match header is required"}
+	case header == "*":
+		return nil, nil
+	case strings.Contains(header, ","):
+		return nil, errorhandler.BadRequestError{Message: "If-Match must contain a single entity tag"}
+	case strings.HasPrefix(header, "W/"):
+		// If-Match uses the strong comparison, which a weak tag never satisfies.
+		return nil, errorhandler.PreconditionFailedError{Message: "weak entity tags cannot be used in If-Match"}
+	}
+
+	unquoted, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	if !ok {
+		return nil, errorhandler.BadRequestError{Message: "invalid If-Match entity tag"}
+	}
+
+	unquoted, _, _ = strings.Cut(unquoted, "-")
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil {
+		// A well-formed tag that this server never issued cannot match the current version.
+		return nil, errorhandler.PreconditionFailedError{Message: "resource version does not match If-Match"}
+	}
+
+	return &version, nil
+}
+
+func required() bool {
+	required, err := strconv.ParseBool(config.ENV.REQUIRE_IF_MATCH)
+	if err != nil {
+		return true
+	}
+	return required
+}
@@ -0,0 +1,81 @@
+package etag
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+)
+
+// ifMatch runs IfMatch on a request with the given If-Match header, left out when empty.
+func ifMatch(t *testing.T, header string) (*int64, error) {
+	t.Helper()
+
+	var version *int64
+	var matchErr error
+	app := fiber.New()
+	app.Put("/", func(c *fiber.Ctx) error {
+		version, matchErr = IfMatch(c)
+		return nil
+	})
+
+	req := httptest.NewRequest(fiber.MethodPut, "/", nil)
+	if header != "" {
+		req.Header.Set(fiber.HeaderIfMatch, header)
+	}
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	return version, matchErr
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		require     string
+		wantVersion int64 // 0 for no version
+		wantStatus  int   // 0 for no error
+	}{
+		{name: "any version", header: "*", require: "true"},
+		{name: "tag from Format", header: Format(3, map[string]string{"title": "go"}), wantVersion: 3},
+		{name: "bare version", header: `"7"`, wantVersion: 7},
+		{name: "weak tag", header: `W/"3-9b2c1f0e5a7d4c38"`, wantStatus: fiber.StatusPreconditionFailed},
+		{name: "tag of another server", header: `"abc"`, wantStatus: fiber.StatusPreconditionFailed},
+		{name: "unquoted", header: "3", wantStatus: fiber.StatusBadRequest},
+		{name: "several tags", header: `"3", "4"`, wantStatus: fiber.StatusBadRequest},
+		{name: "missing, not required", require: "false"},
+		{name: "missing, required", require: "true", wantStatus: fiber.StatusPreconditionRequired},
+		{name: "missing, setting unset", require: "", wantStatus: fiber.StatusPreconditionRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := config.ENV.REQUIRE_IF_MATCH
+			config.ENV.REQUIRE_IF_MATCH = tt.require
+			t.Cleanup(func() { config.ENV.REQUIRE_IF_MATCH = previous })
+
+			version, err := ifMatch(t, tt.header)
+
+			if tt.wantStatus != 0 {
+				if err == nil {
+					t.Fatalf("IfMatch succeeded, want status %d", tt.wantStatus)
+				}
+				if got := errorhandler.From(err).Status; got != tt.wantStatus {
+					t.Errorf("status = %d, want %d", got, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("IfMatch: %v", err)
+			}
+			switch {
+			case tt.wantVersion == 0 && version != nil:
+				t.Errorf("version = %d, want none", *version)
+			case tt.wantVersion != 0 && (version == nil || *version != tt.wantVersion):
+				t.Errorf("version = %v, want %d", version, tt.wantVersion)
+			}
+		})
+	}
+}
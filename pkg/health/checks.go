@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"gorm.io/gorm"
+)
+
+// DBCheck pings the database behind db.
+func DBCheck(db *gorm.DB) Check {
+	return Check{
+		Name: "database",
+		Run: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+	}
+}
+
+// BlobContainerCheck reads the properties of the container, which needs both a reachable storage
+// account and valid credentials.
+func BlobContainerCheck(client *azblob.Client, containerName string) Check {
+	return Check{
+		Name: "blob_storage",
+		Run: func(ctx context.Context) error {
+			_, err := client.ServiceClient().NewContainerClient(containerName).GetProperties(ctx, nil)
+			return err
+		},
+	}
+}
+
+// HTTPCheck sends a GET to url. Any response below 500 counts as reachable, since the check only
+// asks whether the service answers, not whether url is a valid endpoint.
+func HTTPCheck(name string, client *http.Client, url string) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("responded %s", resp.Status)
+			}
+			return nil
+		},
+	}
+}
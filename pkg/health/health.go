@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/logging"
+)
+
+const (
+	StatusUp           = "up"
+	StatusDown         = "down"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check reports whether one dependency is usable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of one Check. Errors are only logged, since /readyz is public and
+// they can name hosts and credentials.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// Report is the body of /readyz.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Checker runs the readiness checks and holds the readiness flag. It starts not ready; the server
+// marks it ready once it is listening and not ready again when it begins shutting down.
+type Checker struct {
+	ready    atomic.Bool
+	timeout  time.Duration
+	cacheFor time.Duration
+	checks   []Check
+
+	mu        sync.Mutex
+	last      Report
+	lastOK    bool
+	checkedAt time.Time
+}
+
+// NewChecker creates a Checker running checks concurrently, each bounded by timeout. A report is
+// reused for cacheFor, so frequent or concurrent probes do not each reach every dependency.
+func NewChecker(timeout time.Duration, cacheFor time.Duration, checks ...Check) *Checker {
+	return &Checker{timeout: timeout, cacheFor: cacheFor, checks: checks}
+}
+
+// SetReady flips the readiness flag.
+func (ch *Checker) SetReady(ready bool) {
+	ch.ready.Store(ready)
+}
+
+// Check runs every check and reports whether all of them passed. When the flag is off the checks
+// are skipped, so a draining instance is taken out of rotation even if its dependencies are fine.
+// Callers arriving while the checks run wait for them and share the report.
+func (ch *Checker) Check(ctx context.Context) (Report, bool) {
+	if !ch.ready.Load() {
+		return Report{Status: StatusShuttingDown}, false
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if !ch.checkedAt.IsZero() && time.Since(ch.checkedAt) < ch.cacheFor {
+		return ch.last, ch.lastOK
+	}
+
+	// The report is shared, so one caller giving up must not turn it into a failure.
+	ch.last, ch.lastOK = ch.run(context.WithoutCancel(ctx))
+	ch.checkedAt = time.Now()
+	return ch.last, ch.lastOK
+}
+
+func (ch *Checker) run(ctx context.Context) (Report, bool) {
+	results := make(map[string]CheckResult, len(ch.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range ch.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, ch.timeout)
+			defer cancel()
+
+			startedAt := time.Now()
+			err := check.Run(checkCtx)
+
+			result := CheckResult{
+				Status:    StatusUp,
+				LatencyMS: float64(time.Since(startedAt).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = StatusDown
+				logging.Ctx(ctx).Warn().Err(err).Str("check", check.Name).Msg("readiness check failed")
+			}
+
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+			return report, false
+		}
+	}
+	return report, true
+}
+
+// Liveness answers /healthz. It only shows that the process serves requests; a dependency outage
+// must not get the process restarted.
+func Liveness(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(Report{Status: StatusUp})
+}
+
+// Readiness answers /readyz with the per-dependency report, and 503 when any check fails or the
+// server is shutting down.
+func (ch *Checker) Readiness(c *fiber.Ctx) error {
+	report, ok := ch.Check(c.UserContext())
+	if !ok {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
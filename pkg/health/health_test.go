@@ -0,0 +1,137 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// countingCheck counts its runs and fails with err when it is set.
+type countingCheck struct {
+	runs atomic.Int32
+	err  atomic.Pointer[error]
+}
+
+func (cc *countingCheck) check(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error {
+		cc.runs.Add(1)
+		if err := cc.err.Load(); err != nil {
+			return *err
+		}
+		return nil
+	}}
+}
+
+func TestCheckerShuttingDown(t *testing.T) {
+	database := &countingCheck{}
+	checker := NewChecker(time.Second, 0, database.check("database"))
+
+	// A checker starts not ready, before the server listens.
+	report, ok := checker.Check(context.Background())
+	if ok || report.Status != StatusShuttingDown {
+		t.Errorf("Check before SetReady = %+v, %v, want %s", report, ok, StatusShuttingDown)
+	}
+
+	checker.SetReady(true)
+	if report, ok := checker.Check(context.Background()); !ok || report.Status != StatusUp {
+		t.Errorf("Check when ready = %+v, %v, want %s", report, ok, StatusUp)
+	}
+
+	checker.SetReady(false)
+	if report, ok := checker.Check(context.Background()); ok || report.Status != StatusShuttingDown {
+		t.Errorf("Check while shutting down = %+v, %v, want %s", report, ok, StatusShuttingDown)
+	}
+	if got := database.runs.Load(); got != 1 {
+		t.Errorf("runs = %d, want 1: the checks are skipped while not ready", got)
+	}
+}
+
+func TestCheckerReportsFailedChecks(t *testing.T) {
+	database, cache := &countingCheck{}, &countingCheck{}
+	failure := errors.New("dial tcp 10.0.0.5:5432: password authentication failed for user admin")
+	cache.err.Store(&failure)
+
+	checker := NewChecker(time.Second, 0, database.check("database"), cache.check("cache"))
+	checker.SetReady(true)
+
+	report, ok := checker.Check(context.Background())
+	if ok || report.Status != StatusDown {
+		t.Fatalf("Check = %+v, %v, want %s", report, ok, StatusDown)
+	}
+	if report.Checks["database"].Status != StatusUp || report.Checks["cache"].Status != StatusDown {
+		t.Errorf("checks = %+v, want database up and cache down", report.Checks)
+	}
+
+	// /readyz is public, so the report must not carry the error.
+	raw, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if strings.Contains(string(raw), "10.0.0.5") {
+		t.Errorf("report %s leaks the check error", raw)
+	}
+}
+
+func TestCheckerCachesReport(t *testing.T) {
+	database := &countingCheck{}
+	checker := NewChecker(time.Second, time.Hour, database.check("database"))
+	checker.SetReady(true)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checker.Check(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if got := database.runs.Load(); got != 1 {
+		t.Errorf("runs = %d, want 1 within the cache period", got)
+	}
+
+	// A failure only shows once the cached report expires.
+	failure := errors.New("down")
+	database.err.Store(&failure)
+	if _, ok := checker.Check(context.Background()); !ok {
+		t.Error("Check did not reuse the cached report")
+	}
+
+	checker.mu.Lock()
+	checker.checkedAt = time.Now().Add(-time.Hour)
+	checker.mu.Unlock()
+	if _, ok := checker.Check(context.Background()); ok {
+		t.Error("Check reused an expired report")
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	checker := NewChecker(time.Second, 0)
+	app := fiber.New()
+	app.Get("/readyz", checker.Readiness)
+
+	status := func() int {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/readyz", nil))
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if got := status(); got != fiber.StatusServiceUnavailable {
+		t.Errorf("status before ready = %d, want %d", got, fiber.StatusServiceUnavailable)
+	}
+	checker.SetReady(true)
+	if got := status(); got != fiber.StatusOK {
+		t.Errorf("status when ready = %d, want %d", got, fiber.StatusOK)
+	}
+}
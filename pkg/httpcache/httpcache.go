@@ -0,0 +1,96 @@
+package httpcache
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/token"
+)
+
+// Policy is the Cache-Control value of a read endpoint.
+type Policy string
+
+const (
+	// Public lets browsers and shared caches reuse the response for a minute, then revalidate.
+	Public Policy = "public, max-age=60"
+	// Private keeps the response out of shared caches and has the client revalidate on every use.
+	Private Policy = "private, no-cache"
+	// NoStore keeps the response out of every cache, for responses that set cookies.
+	NoStore Policy = "no-store"
+)
+
+// For returns Public for resources anyone may read and Private otherwise.
+func For(isPublic bool) Policy {
+	if isPublic {
+		return Public
+	}
+	return Private
+}
+
+// NotModified sets the Cache-Control and, unless lastModified is zero, Last-Modified headers and
+// reports whether the client's copy is current, in which case the handler should reply with 304.
+// The ETag header must already be set. If-None-Match takes precedence over If-Modified-Since.
+func NotModified(c *fiber.Ctx, policy Policy, lastModified time.Time) bool {
+	c.Set(fiber.HeaderCacheControl, string(effective(c, policy)))
+	c.Vary(fiber.HeaderCookie)
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		return false
+	}
+
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		return matchesAny(noneMatch, string(c.Response().Header.Peek(fiber.HeaderETag)))
+	}
+
+	if modifiedSince := c.Get(fiber.HeaderIfModifiedSince); modifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(modifiedSince)
+		if err != nil {
+			return false
+		}
+		// Last-Modified only has second precision.
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// effective narrows policy for the request: a response that sets cookies, such as refreshed
+// tokens, must not be stored at all, and one served to a signed-in client stays out of shared
+// caches since it may depend on who asked.
+func effective(c *fiber.Ctx, policy Policy) Policy {
+	setsCookies := false
+	c.Response().Header.VisitAllCookie(func(_, _ []byte) {
+		setsCookies = true
+	})
+	if setsCookies {
+		return NoStore
+	}
+
+	if policy == Public && (c.Cookies(token.AccessTokenCookieName) != "" || c.Cookies(token.RefreshTokenCookieName) != "") {
+		return Private
+	}
+	return policy
+}
+
+// matchesAny compares the If-None-Match list with etag using the weak comparison.
+func matchesAny(noneMatch string, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(noneMatch) == "*" {
+		return true
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(noneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
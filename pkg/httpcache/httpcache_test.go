@@ -0,0 +1,122 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/token"
+)
+
+const testETag = `"3-9b2c1f0e5a7d4c38"`
+
+var lastModified = time.Date(2026, 1, 2, 3, 4, 5, 600_000_000, time.UTC)
+
+type request struct {
+	method      string
+	headers     map[string]string
+	policy      Policy
+	setsCookies bool
+}
+
+// notModified runs NotModified on a request for a resource tagged testETag and last modified at
+// lastModified, and returns its result with the Cache-Control it set.
+func notModified(t *testing.T, r request) (bool, string) {
+	t.Helper()
+
+	var result bool
+	app := fiber.New()
+	app.All("/", func(c *fiber.Ctx) error {
+		if r.setsCookies {
+			token.SetTokenCookies(c, "access", "refresh")
+		}
+		c.Set(fiber.HeaderETag, testETag)
+		result = NotModified(c, r.policy, lastModified)
+		return nil
+	})
+
+	method := r.method
+	if method == "" {
+		method = fiber.MethodGet
+	}
+	req := httptest.NewRequest(method, "/", nil)
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	return result, resp.Header.Get(fiber.HeaderCacheControl)
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		name    string
+		request request
+		want    bool
+	}{
+		{name: "no validators", request: request{}},
+		{name: "matching tag", request: request{headers: map[string]string{fiber.HeaderIfNoneMatch: testETag}}, want: true},
+		{name: "weak comparison", request: request{headers: map[string]string{fiber.HeaderIfNoneMatch: `"1-a", W/` + testETag}}, want: true},
+		{name: "any tag", request: request{headers: map[string]string{fiber.HeaderIfNoneMatch: "*"}}, want: true},
+		{name: "other tag", request: request{headers: map[string]string{fiber.HeaderIfNoneMatch: `"1-a"`}}},
+		{
+			name: "If-None-Match takes precedence",
+			request: request{headers: map[string]string{
+				fiber.HeaderIfNoneMatch:     `"1-a"`,
+				fiber.HeaderIfModifiedSince: lastModified.Add(time.Hour).Format(http.TimeFormat),
+			}},
+		},
+		{
+			name:    "modified since in the same second",
+			request: request{headers: map[string]string{fiber.HeaderIfModifiedSince: lastModified.Format(http.TimeFormat)}},
+			want:    true,
+		},
+		{
+			name:    "modified since a second before",
+			request: request{headers: map[string]string{fiber.HeaderIfModifiedSince: lastModified.Add(-time.Second).Format(http.TimeFormat)}},
+		},
+		{
+			name:    "invalid date",
+			request: request{headers: map[string]string{fiber.HeaderIfModifiedSince: "yesterday"}},
+		},
+		{
+			name:    "unsafe method",
+			request: request{method: fiber.MethodPut, headers: map[string]string{fiber.HeaderIfNoneMatch: testETag}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.policy = Public
+			if got, _ := notModified(t, tt.request); got != tt.want {
+				t.Errorf("NotModified = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotModifiedNarrowsPolicy(t *testing.T) {
+	signedIn := map[string]string{fiber.HeaderCookie: token.AccessTokenCookieName + "=Bearer abc"}
+
+	tests := []struct {
+		name    string
+		request request
+		want    Policy
+	}{
+		{name: "public", request: request{policy: Public}, want: Public},
+		{name: "private", request: request{policy: Private}, want: Private},
+		{name: "public to a signed-in client", request: request{policy: Public, headers: signedIn}, want: Private},
+		{name: "response setting cookies", request: request{policy: Public, setsCookies: true}, want: NoStore},
+		{name: "private response setting cookies", request: request{policy: Private, headers: signedIn, setsCookies: true}, want: NoStore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := notModified(t, tt.request); got != string(tt.want) {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
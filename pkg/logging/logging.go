@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	stdlog "log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/revandpratama/lognest/config"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Setup configures the global zerolog logger from LOG_LEVEL and LOG_FORMAT, and routes the
+// standard library logger through it so every line shares one format. Loggers taken from a context
+// without a request logger fall back to the global one.
+func Setup() error {
+	level, err := zerolog.ParseLevel(strings.ToLower(config.ENV.LOG_LEVEL))
+	if err != nil || level == zerolog.NoLevel {
+		return fmt.Errorf("invalid LOG_LEVEL %q, expected trace, debug, info, warn, error, fatal, panic or disabled", config.ENV.LOG_LEVEL)
+	}
+	zerolog.SetGlobalLevel(level)
+
+	switch strings.ToLower(config.ENV.LOG_FORMAT) {
+	case "", "json":
+		zerolog.TimeFieldFormat = time.RFC3339Nano
+		log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+	case "console":
+		log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "15:04:05"}).With().Timestamp().Logger()
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT %q, expected json or console", config.ENV.LOG_FORMAT)
+	}
+
+	zerolog.DefaultContextLogger = &log.Logger
+
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(log.Logger)
+
+	return nil
+}
+
+// Ctx returns the logger of the request ctx belongs to, or the global logger outside a request.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	return zerolog.Ctx(ctx)
+}
+
+// With returns a copy of ctx whose logger carries the fields added by fields. The logger already on
+// ctx is left untouched.
+func With(ctx context.Context, fields func(zerolog.Context) zerolog.Context) context.Context {
+	return fields(Ctx(ctx).With()).Logger().WithContext(ctx)
+}
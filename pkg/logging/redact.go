@@ -0,0 +1,19 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Redact replaces a token or other secret with a placeholder that keeps only its scheme and length,
+// enough to tell a missing value from a malformed one without leaking a usable credential.
+func Redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	if scheme, value, ok := strings.Cut(secret, " "); ok {
+		return fmt.Sprintf("%s [redacted %d chars]", scheme, len(value))
+	}
+	return fmt.Sprintf("[redacted %d chars]", len(secret))
+}
@@ -0,0 +1,20 @@
+package logging
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		secret string
+		want   string
+	}{
+		{secret: "", want: ""},
+		{secret: "Bearer eyJhbGciOiJIUzI1NiJ9.e30.sig", want: "Bearer [redacted 28 chars]"},
+		{secret: "eyJhbGciOiJIUzI1NiJ9.e30.sig", want: "[redacted 28 chars]"},
+		{secret: "Basic ", want: "Basic [redacted 0 chars]"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.secret); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.secret, got, tt.want)
+		}
+	}
+}
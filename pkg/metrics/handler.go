@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/revandpratama/lognest/config"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+)
+
+// Handler serves the Registry in the Prometheus exposition format. When METRICS_TOKEN is set,
+// scrapers must send it as a bearer token; production does not register the route without one.
+func Handler() fiber.Handler {
+	serve := adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+
+	return func(c *fiber.Ctx) error {
+		if expected := config.ENV.METRICS_TOKEN; expected != "" {
+			token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+				return errorhandler.UnauthorizedError{Message: "invalid metrics token"}
+			}
+		}
+		return serve(c)
+	}
+}
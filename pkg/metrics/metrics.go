@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "lognest"
+
+// Registry holds every lognest metric plus the Go runtime and process collectors. It is served
+// on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts served requests by method, route template and status.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests served, by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes how long requests take to serve.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time to serve HTTP requests, by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration observes GORM statements by operation and table.
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Time to run database statements issued through GORM, by operation, table and outcome.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "status"})
+
+	// Auth4meRequests counts calls to Auth4me by endpoint, method and status.
+	Auth4meRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth4me",
+		Name:      "requests_total",
+		Help:      "Requests sent to Auth4me, by endpoint path, method and status code, or error when no response arrived.",
+	}, []string{"endpoint", "method", "status"})
+
+	// Auth4meRequestDuration observes how long Auth4me takes to respond.
+	Auth4meRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "auth4me",
+		Name:      "request_duration_seconds",
+		Help:      "Time until Auth4me responded or the call failed, by endpoint path and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint", "method"})
+
+	// StorageUploads counts blob uploads by outcome.
+	StorageUploads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "uploads_total",
+		Help:      "Blob uploads, by outcome.",
+	}, []string{"status"})
+
+	// StorageUploadBytes counts the bytes of successful blob uploads.
+	StorageUploadBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "upload_bytes_total",
+		Help:      "Bytes written to blob storage by successful uploads.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		Auth4meRequests,
+		Auth4meRequestDuration,
+		StorageUploads,
+		StorageUploadBytes,
+	)
+}
+
+// Status is the outcome label of operations that either succeed or fail.
+func Status(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
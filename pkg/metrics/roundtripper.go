@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// InstrumentAuth4me wraps the transport of the Auth4me client so every call is counted and timed.
+// Endpoints are labeled by URL path, which is a fixed set for Auth4me. A nil next uses
+// http.DefaultTransport.
+func InstrumentAuth4me(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		startedAt := time.Now()
+		resp, err := next.RoundTrip(req)
+
+		Auth4meRequestDuration.WithLabelValues(req.URL.Path, req.Method).Observe(time.Since(startedAt).Seconds())
+
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		Auth4meRequests.WithLabelValues(req.URL.Path, req.Method, status).Inc()
+
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
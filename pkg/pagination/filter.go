@@ -0,0 +1,185 @@
+package pagination
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"gorm.io/gorm"
+)
+
+// FilterType is how the value of a filter is parsed before it is compared with the column.
+type FilterType int
+
+const (
+	String FilterType = iota
+	Int
+	Float
+	Bool
+	Time
+	UUID
+)
+
+// FilterField declares a field that a list may be filtered on.
+type FilterField struct {
+	// Column is the column compared with the value.
+	Column string
+	Type   FilterType
+	// Subquery, when set, is a condition with one %s where the comparison on Column goes, for
+	// fields of another table, e.g. "id IN (SELECT project_id FROM ... WHERE %s)".
+	Subquery string
+}
+
+// AllowedFilters maps the field names of filter query parameters to what they filter on.
+type AllowedFilters map[string]FilterField
+
+// Filter is one condition of a list request, from filter[field]=value or filter[field][op]=value.
+type Filter struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+var filterKeyPattern = regexp.MustCompile(`^filter\[([a-z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+var filterOperators = map[string]string{
+	"eq":  "=",
+	"ne":  "<>",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+	"in":  "IN",
+}
+
+// Parse reads the pagination, sort and filter query parameters of a list request. A limit or page
+// below 1 is rejected and a limit above MaxLimit is lowered to it. Filters are only checked against
+// the fields a list allows when ApplyFilters runs.
+func Parse(c *fiber.Ctx) (*Pagination, error) {
+	pagination := new(Pagination)
+	if err := c.QueryParser(pagination); err != nil {
+		return nil, errorhandler.BadRequestError{Message: err.Error()}
+	}
+
+	if c.Query("limit") != "" && pagination.Limit < 1 {
+		return nil, errorhandler.BadRequestError{Message: "limit must be at least 1"}
+	}
+	if c.Query("page") != "" && pagination.Page < 1 {
+		return nil, errorhandler.BadRequestError{Message: "page must be at least 1"}
+	}
+	pagination.Limit = min(pagination.Limit, MaxLimit)
+	if pagination.Mode != "" && pagination.Mode != ModeOffset && pagination.Mode != ModeCursor {
+		return nil, errorhandler.BadRequestError{Message: fmt.Sprintf("mode must be %s or %s", ModeOffset, ModeCursor)}
+	}
+
+	var err error
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		if err != nil || !strings.HasPrefix(string(key), "filter[") {
+			return
+		}
+
+		match := filterKeyPattern.FindStringSubmatch(string(key))
+		if match == nil {
+			err = errorhandler.BadRequestError{Message: fmt.Sprintf("invalid filter parameter %q", key)}
+			return
+		}
+
+		operator := match[2]
+		if operator == "" {
+			operator = "eq"
+		}
+		if _, ok := filterOperators[operator]; !ok {
+			err = errorhandler.BadRequestError{Message: fmt.Sprintf("unsupported filter operator %q", operator)}
+			return
+		}
+
+		pagination.Filters = append(pagination.Filters, Filter{
+			Field:    match[1],
+			Operator: operator,
+			Value:    string(value),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination, nil
+}
+
+// ApplyFilters adds the request's filters to db as conditions. Fields that are not in allowed and
+// values that do not parse as the field's type are rejected with a bad request error. Repeated
+// filters on a field must all match; filter[field][in]=a,b matches either value.
+func ApplyFilters(db *gorm.DB, pagination *Pagination, allowed AllowedFilters) (*gorm.DB, error) {
+	for _, filter := range pagination.Filters {
+		field, ok := allowed[filter.Field]
+		if !ok {
+			return nil, errorhandler.BadRequestError{Message: fmt.Sprintf("cannot filter by %s", filter.Field)}
+		}
+
+		operator := filterOperators[filter.Operator]
+		if field.Type == Bool && operator != "=" && operator != "<>" {
+			return nil, errorhandler.BadRequestError{Message: fmt.Sprintf("filter[%s] only supports eq and ne", filter.Field)}
+		}
+
+		var value any
+		if operator == "IN" {
+			var values []any
+			for _, raw := range strings.Split(filter.Value, ",") {
+				v, err := parseFilterValue(field.Type, strings.TrimSpace(raw))
+				if err != nil {
+					return nil, invalidFilterValue(filter)
+				}
+				values = append(values, v)
+			}
+			value = values
+		} else {
+			v, err := parseFilterValue(field.Type, filter.Value)
+			if err != nil {
+				return nil, invalidFilterValue(filter)
+			}
+			value = v
+		}
+
+		condition := field.Column + " " + operator + " ?"
+		if field.Subquery != "" {
+			condition = fmt.Sprintf(field.Subquery, condition)
+		}
+		db = db.Where(condition, value)
+	}
+
+	return db, nil
+}
+
+func parseFilterValue(filterType FilterType, raw string) (any, error) {
+	switch filterType {
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Float:
+		return strconv.ParseFloat(raw, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Time:
+		// Dates are the start of the day in UTC.
+		if t, err := time.Parse(time.DateOnly, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.RFC3339, raw)
+	case UUID:
+		return uuid.Parse(raw)
+	default:
+		return raw, nil
+	}
+}
+
+func invalidFilterValue(filter Filter) error {
+	key := "filter[" + filter.Field + "]"
+	if filter.Operator != "eq" {
+		key += "[" + filter.Operator + "]"
+	}
+	return errorhandler.BadRequestError{Message: fmt.Sprintf("invalid value for %s", key)}
+}
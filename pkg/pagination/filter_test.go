@@ -0,0 +1,183 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"gorm.io/gorm"
+)
+
+// parseQuery runs Parse on a request with the given query string.
+func parseQuery(t *testing.T, query string) (*Pagination, error) {
+	t.Helper()
+
+	var pagination *Pagination
+	var parseErr error
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		pagination, parseErr = Parse(c)
+		return nil
+	})
+
+	if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/?"+query, nil)); err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	return pagination, parseErr
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantLimit int
+	}{
+		{query: "", wantLimit: DefaultLimit},
+		{query: "limit=1", wantLimit: 1},
+		{query: "limit=50", wantLimit: 50},
+		{query: "limit=100000", wantLimit: MaxLimit},
+	}
+	for _, tt := range tests {
+		pagination, err := parseQuery(t, tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		if got := pagination.GetLimit(); got != tt.wantLimit {
+			t.Errorf("Parse(%q).GetLimit() = %d, want %d", tt.query, got, tt.wantLimit)
+		}
+	}
+}
+
+func TestParseRejectsOutOfRange(t *testing.T) {
+	for _, query := range []string{"limit=0", "limit=-1", "page=0", "page=-3", "limit=abc", "mode=keyset"} {
+		_, err := parseQuery(t, query)
+
+		var badRequest errorhandler.BadRequestError
+		if !errors.As(err, &badRequest) {
+			t.Errorf("Parse(%q) error = %v, want a bad request", query, err)
+		}
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	pagination, err := parseQuery(t, "filter[title]=go&filter[created_at][gte]=2026-01-01&filter[tag][in]=go,sql")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []Filter{
+		{Field: "title", Operator: "eq", Value: "go"},
+		{Field: "created_at", Operator: "gte", Value: "2026-01-01"},
+		{Field: "tag", Operator: "in", Value: "go,sql"},
+	}
+	if !reflect.DeepEqual(pagination.Filters, want) {
+		t.Errorf("Filters = %+v, want %+v", pagination.Filters, want)
+	}
+}
+
+func TestParseRejectsMalformedFilters(t *testing.T) {
+	for _, query := range []string{"filter[Title]=go", "filter[title]]=go", "filter[title][like]=go", "filter[title][eq][x]=go"} {
+		_, err := parseQuery(t, query)
+
+		var badRequest errorhandler.BadRequestError
+		if !errors.As(err, &badRequest) {
+			t.Errorf("Parse(%q) error = %v, want a bad request", query, err)
+		}
+	}
+}
+
+var testFilters = AllowedFilters{
+	"title":      {Column: "title"},
+	"is_public":  {Column: "is_public", Type: Bool},
+	"likes":      {Column: "like_count", Type: Int},
+	"created_at": {Column: "created_at", Type: Time},
+	"owner":      {Column: "user_profile_id", Type: UUID},
+	"tag":        {Column: "tags.name", Subquery: "id IN (SELECT project_id FROM project_tags JOIN tags ON tags.id = tag_id WHERE %s)"},
+}
+
+// filterSQL returns the statement ApplyFilters builds for filters, or the error it returns.
+func filterSQL(t *testing.T, filters ...Filter) (string, error) {
+	t.Helper()
+
+	db, _ := dryRunDB(t)
+	var applyErr error
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		query, err := ApplyFilters(tx.Model(&keysetRow{}), &Pagination{Filters: filters}, testFilters)
+		if err != nil {
+			applyErr = err
+			return tx
+		}
+		var rows []keysetRow
+		return query.Find(&rows)
+	})
+	return sql, applyErr
+}
+
+func TestApplyFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []Filter
+		want    string
+	}{
+		{
+			name:    "equality",
+			filters: []Filter{{Field: "title", Operator: "eq", Value: "go"}},
+			want:    `WHERE title = 'go'`,
+		},
+		{
+			name:    "typed comparison",
+			filters: []Filter{{Field: "likes", Operator: "gte", Value: "3"}},
+			want:    `WHERE like_count >= 3`,
+		},
+		{
+			name:    "in list",
+			filters: []Filter{{Field: "likes", Operator: "in", Value: "1, 2"}},
+			want:    `WHERE like_count IN (1,2)`,
+		},
+		{
+			name:    "repeated filters all apply",
+			filters: []Filter{{Field: "is_public", Operator: "eq", Value: "true"}, {Field: "title", Operator: "ne", Value: "go"}},
+			want:    `WHERE is_public = true AND title <> 'go'`,
+		},
+		{
+			name:    "subquery",
+			filters: []Filter{{Field: "tag", Operator: "eq", Value: "go"}},
+			want:    `WHERE id IN (SELECT project_id FROM project_tags JOIN tags ON tags.id = tag_id WHERE tags.name = 'go')`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := filterSQL(t, tt.filters...)
+			if err != nil {
+				t.Fatalf("ApplyFilters: %v", err)
+			}
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("SQL %q does not contain %q", sql, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFiltersRejectsInvalid(t *testing.T) {
+	tests := map[string]Filter{
+		"unknown field":      {Field: "password", Operator: "eq", Value: "x"},
+		"ordering on a bool": {Field: "is_public", Operator: "gt", Value: "true"},
+		"bad int":            {Field: "likes", Operator: "eq", Value: "many"},
+		"bad int in a list":  {Field: "likes", Operator: "in", Value: "1,two"},
+		"bad time":           {Field: "created_at", Operator: "lt", Value: "yesterday"},
+		"bad uuid":           {Field: "owner", Operator: "eq", Value: "42"},
+	}
+	for name, filter := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := filterSQL(t, filter)
+
+			var badRequest errorhandler.BadRequestError
+			if !errors.As(err, &badRequest) {
+				t.Errorf("ApplyFilters error = %v, want a bad request", err)
+			}
+		})
+	}
+}
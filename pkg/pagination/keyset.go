@@ -0,0 +1,224 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/revandpratama/lognest/pkg/errorhandler"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// The values of the mode query parameter.
+const (
+	ModeOffset = "offset"
+	ModeCursor = "cursor"
+)
+
+// cursor is the position of a row in a keyset-paginated list: its sort keys and ID, and the sort
+// they belong to, so a cursor cannot be replayed against a different order.
+type cursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Values    []any  `json:"v"`
+	ID        any    `json:"i"`
+	// Backward marks a cursor that fetches the rows before this one.
+	Backward bool `json:"b,omitempty"`
+}
+
+// FindKeyset loads one page of rows with keyset pagination: rows are ordered by the sort columns
+// and ID, and a page starts after the row in the request's cursor rather than at an offset, so it
+// neither scans the skipped rows nor skips or repeats rows inserted while paging. The next and
+// previous cursors are set on pagination. Keyset pagination is opted into with a cursor or
+// mode=cursor; any other request is served with offset pagination and its totals. preloads are only
+// applied to the page, never to the count.
+func FindKeyset[T any](db *gorm.DB, pagination *Pagination, allowedSortColumns []string, preloads ...string) ([]T, error) {
+	var rows []T
+
+	if pagination.Cursor == "" && pagination.Mode != ModeCursor {
+		query := Paginate(db, pagination, &rows, allowedSortColumns)
+		for _, preload := range preloads {
+			query = query.Preload(preload)
+		}
+		err := query.Find(&rows).Error
+		return rows, err
+	}
+
+	var position *cursor
+	if pagination.Cursor != "" {
+		decoded, err := decodeCursor(pagination.Cursor)
+		if err != nil {
+			return nil, errorhandler.BadRequestError{Message: "invalid cursor"}
+		}
+		position = decoded
+		pagination.SortBy = decoded.SortBy
+		pagination.SortOrder = decoded.SortOrder
+	}
+
+	sorts := pagination.Sorts(allowedSortColumns)
+	if position != nil && (position.SortBy != pagination.SortBy || len(position.Values) != len(sorts)) {
+		return nil, errorhandler.BadRequestError{Message: "invalid cursor"}
+	}
+
+	statement := &gorm.Statement{DB: db}
+	if err := statement.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	idField := statement.Schema.PrioritizedPrimaryField
+	if idField == nil {
+		return nil, fmt.Errorf("cannot paginate %s without a primary key", statement.Schema.Name)
+	}
+	sortFields := make([]*schema.Field, 0, len(sorts))
+	for _, sort := range sorts {
+		field := statement.Schema.LookUpField(sort.Column)
+		if field == nil {
+			return nil, fmt.Errorf("cannot paginate %s by %s", statement.Schema.Name, sort.Column)
+		}
+		sortFields = append(sortFields, field)
+	}
+
+	if pagination.IncludeTotal {
+		var totalRows int64
+		if err := db.Session(&gorm.Session{}).Model(new(T)).Count(&totalRows).Error; err != nil {
+			return nil, err
+		}
+		pagination.TotalRows = &totalRows
+	}
+
+	// Paging backward scans in the opposite direction; the rows are put back in order below. The
+	// ID breaks ties in the direction of the first sort column.
+	backward := position != nil && position.Backward
+	keys := make([]Sort, 0, len(sorts)+1)
+	for i, sort := range sorts {
+		keys = append(keys, Sort{Column: sortFields[i].DBName, Descending: sort.Descending != backward})
+	}
+	keys = append(keys, Sort{Column: idField.DBName, Descending: keys[0].Descending})
+
+	query := db.Session(&gorm.Session{})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	if position != nil {
+		condition, values := after(keys, append(slices.Clone(position.Values), position.ID))
+		query = query.Where(condition, values...)
+	}
+
+	order := make([]string, 0, len(keys))
+	for _, key := range keys {
+		order = append(order, key.Column+" "+key.Direction())
+	}
+
+	// One extra row tells whether there is another page in the scan direction.
+	err := query.
+		Order(strings.Join(order, ", ")).
+		Limit(pagination.GetLimit() + 1).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(rows) > pagination.GetLimit()
+	if hasMore {
+		rows = rows[:pagination.GetLimit()]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	pagination.NextCursor, pagination.PrevCursor = "", ""
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	ctx := db.Statement.Context
+	keyOf := func(row *T, backward bool) string {
+		value := reflect.ValueOf(row).Elem()
+		values := make([]any, 0, len(sortFields))
+		for _, field := range sortFields {
+			sortValue, _ := field.ValueOf(ctx, value)
+			values = append(values, sortValue)
+		}
+		id, _ := idField.ValueOf(ctx, value)
+		return encodeCursor(cursor{
+			SortBy:    pagination.SortBy,
+			SortOrder: pagination.SortOrder,
+			Values:    values,
+			ID:        id,
+			Backward:  backward,
+		})
+	}
+
+	// Coming back from a later page means there is a next page, and vice versa.
+	if hasMore || backward {
+		pagination.NextCursor = keyOf(&rows[len(rows)-1], false)
+	}
+	if (backward && hasMore) || (!backward && position != nil) {
+		pagination.PrevCursor = keyOf(&rows[0], true)
+	}
+
+	return rows, nil
+}
+
+// after builds the condition matching the rows that come after values in the order of keys. When
+// every key has the same direction it is a single row comparison, which an index on the keys can
+// serve; otherwise it expands to (a > ?) OR (a = ? AND b < ?) OR ...
+func after(keys []Sort, values []any) (string, []any) {
+	comparison := func(key Sort) string {
+		if key.Descending {
+			return "<"
+		}
+		return ">"
+	}
+
+	if !slices.ContainsFunc(keys, func(key Sort) bool { return key.Descending != keys[0].Descending }) {
+		columns := make([]string, 0, len(keys))
+		for _, key := range keys {
+			columns = append(columns, key.Column)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), comparison(keys[0]), placeholders), values
+	}
+
+	var conditions []string
+	var args []any
+	for i, key := range keys {
+		terms := make([]string, 0, i+1)
+		for j := range i {
+			terms = append(terms, keys[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+		terms = append(terms, key.Column+" "+comparison(key)+" ?")
+		args = append(args, values[i])
+		conditions = append(conditions, "("+strings.Join(terms, " AND ")+")")
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+func encodeCursor(c cursor) string {
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	if len(c.Values) == 0 || slices.Contains(c.Values, nil) || c.ID == nil {
+		return nil, errors.New("incomplete cursor")
+	}
+	return &c, nil
+}
@@ -0,0 +1,194 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := cursor{
+		SortBy:    "title,created_at",
+		SortOrder: "ASC,DESC",
+		Values:    []any{"go", "2026-01-02T03:04:05Z"},
+		ID:        "7f1c2a53-8f5e-4a39-9d2c-1a6e0c3b5d41",
+		Backward:  true,
+	}
+
+	got, err := decodeCursor(encodeCursor(want))
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("decodeCursor(encodeCursor(c)) = %+v, want %+v", *got, want)
+	}
+}
+
+func TestDecodeCursorRejectsMalformed(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := map[string]string{
+		"not base64":   "!!!",
+		"not json":     encode("not json"),
+		"no values":    encode(`{"s":"created_at","o":"DESC","v":[],"i":"1"}`),
+		"null value":   encode(`{"s":"created_at","o":"DESC","v":[null],"i":"1"}`),
+		"no id":        encode(`{"s":"created_at","o":"DESC","v":["2026-01-02T03:04:05Z"]}`),
+		"padded input": base64.URLEncoding.EncodeToString([]byte(`{"v":["a"],"i":"1"}`)) + "=",
+	}
+	for name, encoded := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeCursor(encoded); err == nil {
+				t.Errorf("decodeCursor(%q) succeeded, want an error", encoded)
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		name          string
+		keys          []Sort
+		values        []any
+		wantCondition string
+		wantArgs      []any
+	}{
+		{
+			name:          "same direction uses a row comparison",
+			keys:          []Sort{{Column: "created_at", Descending: true}, {Column: "id", Descending: true}},
+			values:        []any{"t", "1"},
+			wantCondition: "(created_at, id) < (?, ?)",
+			wantArgs:      []any{"t", "1"},
+		},
+		{
+			name:          "ascending",
+			keys:          []Sort{{Column: "title"}, {Column: "id"}},
+			values:        []any{"go", "1"},
+			wantCondition: "(title, id) > (?, ?)",
+			wantArgs:      []any{"go", "1"},
+		},
+		{
+			name:          "mixed directions expand per key",
+			keys:          []Sort{{Column: "like_count", Descending: true}, {Column: "title"}, {Column: "id", Descending: true}},
+			values:        []any{3, "go", "1"},
+			wantCondition: "((like_count < ?) OR (like_count = ? AND title > ?) OR (like_count = ? AND title = ? AND id < ?))",
+			wantArgs:      []any{3, 3, "go", 3, "go", "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, args := after(tt.keys, tt.values)
+			if condition != tt.wantCondition {
+				t.Errorf("condition = %q, want %q", condition, tt.wantCondition)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+type keysetRow struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key"`
+	Title     string
+	CreatedAt time.Time
+}
+
+// dryRunDB returns a database that builds statements without a server and reports the LIMIT of
+// the last query through limit.
+func dryRunDB(t *testing.T) (*gorm.DB, *int) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	limit := -1
+	err = db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		if c, ok := tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit); ok && c.Limit != nil {
+			limit = *c.Limit
+		}
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return db, &limit
+}
+
+func TestFindKeysetLimit(t *testing.T) {
+	tests := []struct {
+		limit     int
+		wantLimit int
+	}{
+		{limit: 0, wantLimit: DefaultLimit + 1},
+		{limit: 1, wantLimit: 2},
+		{limit: 25, wantLimit: 26},
+		{limit: -5, wantLimit: DefaultLimit + 1},
+	}
+	for _, tt := range tests {
+		db, limit := dryRunDB(t)
+		pagination := &Pagination{Limit: tt.limit, Mode: ModeCursor}
+
+		rows, err := FindKeyset[keysetRow](db, pagination, []string{"created_at", "title"})
+		if err != nil {
+			t.Fatalf("limit %d: FindKeyset: %v", tt.limit, err)
+		}
+		if len(rows) != 0 {
+			t.Errorf("limit %d: got %d rows from a dry run", tt.limit, len(rows))
+		}
+
+		// One row more than the page tells whether there is a next page.
+		if *limit != tt.wantLimit {
+			t.Errorf("limit %d: LIMIT %d, want %d", tt.limit, *limit, tt.wantLimit)
+		}
+	}
+}
+
+func TestFindKeysetDefaultsToOffset(t *testing.T) {
+	db, limit := dryRunDB(t)
+	pagination := &Pagination{Limit: 5}
+
+	if _, err := FindKeyset[keysetRow](db, pagination, []string{"created_at"}); err != nil {
+		t.Fatalf("FindKeyset: %v", err)
+	}
+
+	// Without a cursor or mode=cursor the list stays offset-paginated, with its totals.
+	if *limit != 5 {
+		t.Errorf("LIMIT %d, want 5", *limit)
+	}
+	if pagination.TotalRows == nil || pagination.TotalPages == nil {
+		t.Error("totals are not set in offset mode")
+	}
+	if pagination.NextCursor != "" || pagination.PrevCursor != "" {
+		t.Error("cursors are set in offset mode")
+	}
+}
+
+func TestFindKeysetRejectsForeignCursor(t *testing.T) {
+	db, _ := dryRunDB(t)
+
+	encoded := encodeCursor(cursor{SortBy: "title", SortOrder: "ASC", Values: []any{"go"}, ID: "1"})
+	pagination := &Pagination{Cursor: encoded, SortBy: "created_at"}
+
+	// The cursor's own sort wins over the query's, so a cursor for a sort that is not allowed no
+	// longer matches once the disallowed column is dropped.
+	if _, err := FindKeyset[keysetRow](db, pagination, []string{"created_at"}); err == nil {
+		t.Error("FindKeyset accepted a cursor for a sort column that is not allowed")
+	}
+
+	pagination = &Pagination{Cursor: "garbage"}
+	if _, err := FindKeyset[keysetRow](db, pagination, []string{"created_at"}); err == nil {
+		t.Error("FindKeyset accepted a malformed cursor")
+	}
+}
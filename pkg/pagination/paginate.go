@@ -8,24 +8,94 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	// DefaultLimit is the page size when the request gives none.
+	DefaultLimit = 10
+	// MaxLimit bounds the page size a client may ask for.
+	MaxLimit = 100
+)
+
 type Pagination struct {
 	Limit      int    `json:"limit,omitempty" query:"limit"`
 	Page       int    `json:"page,omitempty" query:"page"`
-	TotalRows  int64  `json:"total_rows" query:"total_rows"`
-	TotalPages int    `json:"total_pages" query:"total_pages"`
+	TotalRows  *int64 `json:"total_rows,omitempty" query:"-"`
+	TotalPages *int   `json:"total_pages,omitempty" query:"-"`
 	SortBy     string `json:"sort_by,omitempty" query:"sort_by"`
 	SortOrder  string `json:"sort_order,omitempty" query:"sort_order"`
+
+	// Keyset pagination, used for a cursor or mode=cursor. The totals are left out unless
+	// IncludeTotal is set.
+	Mode         string `json:"-" query:"mode"`
+	Cursor       string `json:"-" query:"cursor"`
+	IncludeTotal bool   `json:"-" query:"include_total"`
+	NextCursor   string `json:"next_cursor,omitempty" query:"-"`
+	PrevCursor   string `json:"prev_cursor,omitempty" query:"-"`
+
+	// Filters are read by Parse and applied by ApplyFilters.
+	Filters []Filter `json:"-" query:"-"`
+}
+
+// Sort is one column of the order of a list.
+type Sort struct {
+	Column     string
+	Descending bool
+}
+
+// Sorts returns the requested order from sort_by and sort_order, which both take comma-separated
+// lists, e.g. sort_by=like_count,created_at&sort_order=desc,asc. A column without its own order
+// uses the last one given, DESC by default. Columns that are not allowed are dropped, and without
+// any allowed column the list is ordered by created_at DESC. SortBy and SortOrder are rewritten to
+// the order that applies.
+func (p *Pagination) Sorts(allowedSortColumns []string) []Sort {
+	orders := strings.Split(p.SortOrder, ",")
+
+	var sorts []Sort
+	descending := true
+	for i, column := range strings.Split(p.SortBy, ",") {
+		if i < len(orders) {
+			descending = strings.ToUpper(strings.TrimSpace(orders[i])) != "ASC"
+		}
+
+		column = strings.TrimSpace(column)
+		if !slices.Contains(allowedSortColumns, column) || slices.ContainsFunc(sorts, func(s Sort) bool { return s.Column == column }) {
+			continue
+		}
+		sorts = append(sorts, Sort{Column: column, Descending: descending})
+	}
+
+	if len(sorts) == 0 {
+		sorts = []Sort{{Column: "created_at", Descending: true}}
+	}
+
+	columns := make([]string, 0, len(sorts))
+	directions := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		columns = append(columns, sort.Column)
+		directions = append(directions, sort.Direction())
+	}
+	p.SortBy = strings.Join(columns, ",")
+	p.SortOrder = strings.Join(directions, ",")
+
+	return sorts
+}
+
+// Direction is the SQL keyword of the sort direction.
+func (s Sort) Direction() string {
+	if s.Descending {
+		return "DESC"
+	}
+	return "ASC"
 }
 
 func (p *Pagination) GetLimit() int {
-	if p.Limit == 0 {
-		p.Limit = 10
+	if p.Limit < 1 {
+		p.Limit = DefaultLimit
 	}
 	return p.Limit
 }
 
 func (p *Pagination) GetPage() int {
-	if p.Page == 0 {
+	if p.Page < 1 {
 		p.Page = 1
 	}
 	return p.Page
@@ -39,25 +109,16 @@ func Paginate(db *gorm.DB, pagination *Pagination, model any, allowedSortColumns
 	var totalRows int64
 	db.Model(model).Count(&totalRows)
 
-	pagination.TotalRows = totalRows
-	pagination.TotalPages = int(math.Ceil(float64(totalRows) / float64(pagination.GetLimit())))
-
-	isColumnAllowed := false
-	if slices.Contains(allowedSortColumns, pagination.SortBy) {
-		isColumnAllowed = true
-	}
-
-	if !isColumnAllowed {
-		db = db.Order("created_at desc")
-	} else {
+	totalPages := int(math.Ceil(float64(totalRows) / float64(pagination.GetLimit())))
 
-		sortOrder := "DESC" // Default to a safe value
-		if strings.ToUpper(pagination.SortOrder) == "ASC" {
-			sortOrder = "ASC"
-		}
+	pagination.TotalRows = &totalRows
+	pagination.TotalPages = &totalPages
 
-		db = db.Order(pagination.SortBy + " " + sortOrder)
+	var order []string
+	for _, sort := range pagination.Sorts(allowedSortColumns) {
+		order = append(order, sort.Column+" "+sort.Direction())
 	}
+	db = db.Order(strings.Join(order, ", "))
 
 	return db.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
 }
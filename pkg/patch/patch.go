@@ -0,0 +1,42 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ContentType is the media type of a JSON Merge Patch (RFC 7386) document.
+const ContentType = "application/merge-patch+json"
+
+// Field is one member of a JSON Merge Patch document. Unlike a pointer it tells apart a member
+// that is absent (leave the value alone), null (remove the value) and set, including to an
+// empty or zero value.
+type Field[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+// UnmarshalJSON is only called for members present in the document, which marks the field as set.
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		var zero T
+		f.Value = zero
+		f.Null = true
+		return nil
+	}
+
+	f.Null = false
+	return json.Unmarshal(data, &f.Value)
+}
+
+// Interface returns the value to validate: nil when the member is absent or null, so that
+// omitempty skips it and required rejects it.
+func (f Field[T]) Interface() any {
+	if !f.Set || f.Null {
+		return nil
+	}
+	return f.Value
+}
@@ -0,0 +1,97 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/revandpratama/lognest/config"
+)
+
+const (
+	AccessTokenCookieName  = "access_token"
+	RefreshTokenCookieName = "refresh_token"
+
+	AccessTokenCookieTTL  = 5 * time.Minute
+	RefreshTokenCookieTTL = 24 * time.Hour
+)
+
+// SetTokenCookies writes the access and refresh token cookies issued by Auth4me.
+func SetTokenCookies(c *fiber.Ctx, accessToken string, refreshToken string) {
+	accessTokenCookie := &fiber.Cookie{
+		Name:     AccessTokenCookieName,
+		Value:    accessToken,
+		Expires:  time.Now().Add(AccessTokenCookieTTL),
+		HTTPOnly: true,
+	}
+
+	if config.ENV.APP_ENV == "production" {
+		accessTokenCookie.SameSite = "None"
+		accessTokenCookie.Domain = ".revandpratama.com"
+		accessTokenCookie.Secure = true
+	}
+
+	c.Cookie(accessTokenCookie)
+
+	refreshTokenCookie := &fiber.Cookie{
+		Name:     RefreshTokenCookieName,
+		Value:    refreshToken,
+		Expires:  time.Now().Add(RefreshTokenCookieTTL),
+		HTTPOnly: true,
+	}
+
+	if config.ENV.APP_ENV == "production" {
+		refreshTokenCookie.SameSite = "None"
+		refreshTokenCookie.Domain = ".revandpratama.com"
+		refreshTokenCookie.Secure = true
+	}
+
+	c.Cookie(refreshTokenCookie)
+}
+
+// ClearTokenCookies expires both token cookies on the client.
+func ClearTokenCookies(c *fiber.Ctx) {
+	accessTokenCookie := &fiber.Cookie{
+		Name:     AccessTokenCookieName,
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour), // Set expiration to the past
+		HTTPOnly: true,
+		Path:     "/",
+	}
+
+	// The domain must match the one the cookie was set with, or the browser keeps it.
+	if config.ENV.APP_ENV == "production" {
+		accessTokenCookie.SameSite = "None"
+		accessTokenCookie.Domain = ".revandpratama.com"
+		accessTokenCookie.Secure = true
+	}
+
+	c.Cookie(accessTokenCookie)
+
+	refreshTokenCookie := &fiber.Cookie{
+		Name:     RefreshTokenCookieName,
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour),
+		HTTPOnly: true,
+		Path:     "/",
+	}
+
+	if config.ENV.APP_ENV == "production" {
+		refreshTokenCookie.SameSite = "None"
+		refreshTokenCookie.Domain = ".revandpratama.com"
+		refreshTokenCookie.Secure = true
+	}
+
+	c.Cookie(refreshTokenCookie)
+}
+
+// ExtractBearer returns the raw JWT from a "Bearer <token>" value.
+func ExtractBearer(value string) (string, error) {
+	parts := strings.Split(value, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "", errors.New("invalid token format")
+	}
+
+	return parts[1], nil
+}
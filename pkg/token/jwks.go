@@ -0,0 +1,240 @@
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// minRefetchInterval rate-limits refetches triggered by tokens carrying an unknown kid.
+const minRefetchInterval = 30 * time.Second
+
+// JSONWebKey is the subset of RFC 7517 fields needed to verify RS256 and EdDSA signatures.
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type jsonWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// KeySet caches the public keys of a JWKS document, indexed by kid.
+type KeySet struct {
+	url        string
+	file       string
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]any
+	fetchedAt time.Time
+
+	refetchMu sync.Mutex
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewKeySet creates a KeySet backed by a JWKS URL or, when url is empty, by a local file.
+func NewKeySet(url string, file string, httpClient *http.Client) (*KeySet, error) {
+	if url == "" && file == "" {
+		return nil, errors.New("either a JWKS URL or a JWKS file is required")
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &KeySet{
+		url:        url,
+		file:       file,
+		httpClient: httpClient,
+		keys:       make(map[string]any),
+		stopCh:     make(chan struct{}),
+	}, nil
+}
+
+// Refresh reloads the key set from its source, replacing the cached keys on success.
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	raw, err := ks.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	var set jsonWebKeySet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			log.Warn().Err(err).Str("kid", jwk.Kid).Msg("skipping unusable JWKS key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return errors.New("JWKS contains no usable signing keys")
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// Key returns the public key for kid, refetching the set once if the kid is unknown
+// so that keys rotated in by the issuer are picked up without waiting for the next refresh.
+func (ks *KeySet) Key(kid string) (any, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	fetchedAt := ks.fetchedAt
+	ks.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	ks.refetchMu.Lock()
+	defer ks.refetchMu.Unlock()
+
+	// Another caller may have refetched while we were waiting for the lock.
+	ks.mu.RLock()
+	fetchedAt = ks.fetchedAt
+	ks.mu.RUnlock()
+
+	if time.Since(fetchedAt) >= minRefetchInterval {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := ks.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ks.mu.RLock()
+	key, ok = ks.keys[kid]
+	ks.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return key, nil
+}
+
+// StartAutoRefresh refreshes the key set every interval until Close is called.
+func (ks *KeySet) StartAutoRefresh(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				if err := ks.Refresh(ctx); err != nil {
+					log.Warn().Err(err).Msg("failed to refresh JWKS, keeping cached keys")
+				}
+				cancel()
+			case <-ks.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background refresh.
+func (ks *KeySet) Close() {
+	ks.stopOnce.Do(func() {
+		close(ks.stopCh)
+	})
+}
+
+func (ks *KeySet) load(ctx context.Context) ([]byte, error) {
+	if ks.url == "" {
+		return os.ReadFile(ks.file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ks.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// PublicKey converts the JWK into an *rsa.PublicKey or ed25519.PublicKey.
+func (k JSONWebKey) PublicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Ed25519 key: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key length")
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
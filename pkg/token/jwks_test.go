@@ -0,0 +1,252 @@
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/revandpratama/lognest/config"
+)
+
+// jwksServer serves a JWKS document that tests can swap, and counts the fetches.
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    []JSONWebKey
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, keys ...JSONWebKey) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) rotate(keys ...JSONWebKey) {
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+}
+
+func newEd25519Key(t *testing.T, kid string) (ed25519.PrivateKey, JSONWebKey) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey: %v", err)
+	}
+	return private, JSONWebKey{
+		Kid: kid,
+		Kty: "OKP",
+		Crv: "Ed25519",
+		Use: "sig",
+		X:   base64.RawURLEncoding.EncodeToString(public),
+	}
+}
+
+// expireFetch makes the key set eligible for a refetch on the next unknown kid.
+func expireFetch(ks *KeySet) {
+	ks.mu.Lock()
+	ks.fetchedAt = time.Now().Add(-minRefetchInterval)
+	ks.mu.Unlock()
+}
+
+func TestKeySetPicksUpRotatedKeys(t *testing.T) {
+	_, oldJWK := newEd25519Key(t, "old")
+	_, newJWK := newEd25519Key(t, "new")
+	server := newJWKSServer(t, oldJWK)
+
+	ks, err := NewKeySet(server.URL, "", server.Client())
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, err := ks.Key("old"); err != nil {
+		t.Fatalf("Key(old): %v", err)
+	}
+
+	server.rotate(oldJWK, newJWK)
+
+	// Right after a fetch an unknown kid does not hit the issuer again.
+	if _, err := ks.Key("new"); err == nil {
+		t.Fatal("Key(new) succeeded without a refetch")
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Fatalf("fetches = %d, want 1 within the refetch interval", got)
+	}
+
+	expireFetch(ks)
+	if _, err := ks.Key("new"); err != nil {
+		t.Fatalf("Key(new) after the refetch interval: %v", err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+
+	// Keys the issuer drops stop verifying after the next refresh.
+	server.rotate(newJWK)
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, err := ks.Key("old"); err == nil {
+		t.Error("Key(old) still succeeds after the issuer dropped it")
+	}
+}
+
+func TestKeySetRefreshKeepsKeysOnFailure(t *testing.T) {
+	_, jwk := newEd25519Key(t, "current")
+	server := newJWKSServer(t, jwk)
+
+	ks, err := NewKeySet(server.URL, "", server.Client())
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	server.rotate(JSONWebKey{Kid: "enc", Kty: "OKP", Crv: "Ed25519", Use: "enc", X: jwk.X})
+	if err := ks.Refresh(context.Background()); err == nil {
+		t.Error("Refresh accepted a set without signing keys")
+	}
+	if _, err := ks.Key("current"); err != nil {
+		t.Errorf("Key(current) after a failed refresh: %v", err)
+	}
+}
+
+func TestKeySetFromFile(t *testing.T) {
+	_, jwk := newEd25519Key(t, "file")
+	raw, err := json.Marshal(jsonWebKeySet{Keys: []JSONWebKey{jwk}})
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+
+	ks, err := NewKeySet("", path, nil)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	if _, err := ks.Key("file"); err != nil {
+		t.Errorf("Key(file): %v", err)
+	}
+
+	if _, err := NewKeySet("", "", nil); err == nil {
+		t.Error("NewKeySet accepted neither a URL nor a file")
+	}
+}
+
+func TestJSONWebKeyPublicKey(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	rsaJWK := JSONWebKey{
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+	}
+
+	key, err := rsaJWK.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey: %v", err)
+	}
+	if !private.PublicKey.Equal(key) {
+		t.Error("RSA key does not match the JWK")
+	}
+
+	invalid := map[string]JSONWebKey{
+		"unknown type":      {Kty: "EC"},
+		"other curve":       {Kty: "OKP", Crv: "X25519", X: "AAAA"},
+		"short Ed25519":     {Kty: "OKP", Crv: "Ed25519", X: "AAAA"},
+		"malformed modulus": {Kty: "RSA", N: "!!", E: "AQAB"},
+	}
+	for name, jwk := range invalid {
+		if _, err := jwk.PublicKey(); err == nil {
+			t.Errorf("%s: PublicKey succeeded", name)
+		}
+	}
+}
+
+func TestValidateTokenWithRotatedKey(t *testing.T) {
+	previousAlgorithm, previousSecret := config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET
+	config.ENV.JWT_ALGORITHM = "EdDSA"
+	config.ENV.JWT_SECRET = "shared-secret"
+	t.Cleanup(func() {
+		config.ENV.JWT_ALGORITHM, config.ENV.JWT_SECRET = previousAlgorithm, previousSecret
+		SetKeySet(nil)
+	})
+
+	oldKey, oldJWK := newEd25519Key(t, "old")
+	newKey, newJWK := newEd25519Key(t, "new")
+	server := newJWKSServer(t, oldJWK)
+
+	ks, err := NewKeySet(server.URL, "", server.Client())
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	SetKeySet(ks)
+
+	sign := func(kid string, key ed25519.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, CustomClaims{
+			UserID:           "user-1",
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+		})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString: %v", err)
+		}
+		return signed
+	}
+
+	if claims, err := ValidateToken(sign("old", oldKey)); err != nil || claims.UserID != "user-1" {
+		t.Fatalf("ValidateToken(old) = %+v, %v", claims, err)
+	}
+
+	server.rotate(oldJWK, newJWK)
+	expireFetch(ks)
+	if _, err := ValidateToken(sign("new", newKey)); err != nil {
+		t.Errorf("ValidateToken(new) after rotation: %v", err)
+	}
+
+	if _, err := ValidateToken(sign("new", oldKey)); err == nil {
+		t.Error("ValidateToken accepted a token signed with another key than its kid")
+	}
+
+	hs256, err := jwt.NewWithClaims(jwt.SigningMethodHS256, CustomClaims{UserID: "user-1"}).SignedString([]byte("shared-secret"))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	if _, err := ValidateToken(hs256); err == nil {
+		t.Error("ValidateToken accepted an HS256 token while EdDSA is configured")
+	}
+}
@@ -2,11 +2,19 @@ package token
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/revandpratama/lognest/config"
 )
 
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
 type CustomClaims struct {
 	UserID       string `json:"user_id"`
 	Email        string `json:"email"`
@@ -17,26 +25,65 @@ type CustomClaims struct {
 	jwt.RegisteredClaims
 }
 
-func ValidateToken(tokenString string) (*CustomClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(t *jwt.Token) (any, error) {
+// keySet holds the asymmetric verification keys; it stays nil for HS256 deployments.
+var keySet *KeySet
+
+// SetKeySet installs the JWKS used to verify RS256/EdDSA tokens.
+func SetKeySet(ks *KeySet) {
+	keySet = ks
+}
+
+// Algorithm returns the configured verification algorithm, defaulting to HS256.
+func Algorithm() string {
+	switch strings.ToUpper(config.ENV.JWT_ALGORITHM) {
+	case "RS256":
+		return AlgorithmRS256
+	case "EDDSA":
+		return AlgorithmEdDSA
+	default:
+		return AlgorithmHS256
+	}
+}
+
+// keyFunc resolves the verification key, pinning the signing method to the configured
+// algorithm so a token can never pick its own (e.g. HS256 signed with a public key).
+func keyFunc(t *jwt.Token) (any, error) {
+	if t.Method.Alg() != Algorithm() {
+		return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+	}
+
+	if Algorithm() == AlgorithmHS256 {
 		return []byte(config.ENV.JWT_SECRET), nil
-	})
+	}
+
+	if keySet == nil {
+		return nil, errors.New("no JWKS configured for asymmetric verification")
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("missing kid in token header")
+	}
+
+	return keySet.Key(kid)
+}
+
+func ValidateToken(tokenString string) (*CustomClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, keyFunc, jwt.WithValidMethods([]string{Algorithm()}))
 	if err != nil {
 		return nil, err
 	}
 
 	claims, ok := token.Claims.(*CustomClaims)
 	if !ok || !token.Valid {
-		return nil, err
+		return nil, errors.New("invalid token claims")
 	}
 
 	return claims, nil
 }
 
 func ParseExpiredToken(tokenString string) (*CustomClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(t *jwt.Token) (any, error) {
-		return []byte(config.ENV.JWT_SECRET), nil
-	}, jwt.WithoutClaimsValidation())
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, keyFunc, jwt.WithValidMethods([]string{Algorithm()}), jwt.WithoutClaimsValidation())
 
 	if err != nil {
 		return nil, err
@@ -47,10 +94,6 @@ func ParseExpiredToken(tokenString string) (*CustomClaims, error) {
 		return nil, errors.New("invalid token claims")
 	}
 
-	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-		return nil, errors.New("unexpected signing method")
-	}
-
 	// Manually validate expiration if needed
 	// For refresh flow, we expect it to be expired
 	if claims.ExpiresAt == nil {
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentAuth4me wraps the transport of the Auth4me client so every call gets a client span,
+// and sends the span's trace context in the traceparent header so Auth4me can continue the trace.
+// A nil next uses http.DefaultTransport.
+func InstrumentAuth4me(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx, span := Tracer.Start(req.Context(), fmt.Sprintf("auth4me %s %s", req.Method, req.URL.Path),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.URLPath(req.URL.Path),
+				semconv.ServerAddress(req.URL.Hostname()),
+			),
+		)
+
+		// A RoundTripper must not modify the caller's request.
+		req = req.Clone(ctx)
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := next.RoundTrip(req)
+
+		spanErr := err
+		if err == nil {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+			if resp.StatusCode >= http.StatusInternalServerError {
+				spanErr = fmt.Errorf("auth4me responded %s", resp.Status)
+			}
+		}
+		End(span, spanErr)
+
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,272 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/revandpratama/lognest/pkg/patch"
+)
+
+// DefaultLocale is used when the request does not ask for a supported language.
+const DefaultLocale = "en"
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var (
+	validate   *validator.Validate
+	translator *ut.UniversalTranslator
+	locales    = []string{"en", "id"}
+)
+
+// failedMessages summarize a validation failure in the response message, per locale.
+var failedMessages = map[string]string{
+	"en": "validation failed",
+	"id": "validasi gagal",
+}
+
+// customMessages are the translations of the rules registered by this package, per locale.
+var customMessages = map[string]map[string]string{
+	"en": {
+		"slug":                 "{0} must contain only lowercase letters, numbers and single dashes",
+		"uuid":                 "{0} must be a valid UUID",
+		"filepath_prefix":      "{0} must be a relative storage path",
+		"filepath_prefix_with": "{0} must be a relative storage path starting with {1}",
+	},
+	"id": {
+		"slug":                 "{0} hanya boleh berisi huruf kecil, angka, dan tanda hubung tunggal",
+		"uuid":                 "{0} harus berupa UUID yang valid",
+		"filepath_prefix":      "{0} harus berupa path penyimpanan relatif",
+		"filepath_prefix_with": "{0} harus berupa path penyimpanan relatif yang diawali {1}",
+	},
+}
+
+func init() {
+	validate = validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON name so messages match what the client sent.
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	// Merge patch fields are validated by their value; absent and null members count as empty.
+	RegisterPatchFields(
+		patch.Field[string]{},
+		patch.Field[bool]{},
+		patch.Field[int]{},
+		patch.Field[int64]{},
+		patch.Field[float64]{},
+		patch.Field[uuid.UUID]{},
+		patch.Field[time.Time]{},
+	)
+
+	mustRegister("slug", isSlug)
+	mustRegister("uuid", isUUID)
+	mustRegister("filepath_prefix", hasFilePathPrefix)
+
+	enLocale := en.New()
+	translator = ut.New(enLocale, enLocale, id.New())
+
+	for _, locale := range locales {
+		trans, _ := translator.GetTranslator(locale)
+
+		var err error
+		switch locale {
+		case "id":
+			err = idTranslations.RegisterDefaultTranslations(validate, trans)
+		default:
+			err = enTranslations.RegisterDefaultTranslations(validate, trans)
+		}
+		if err != nil {
+			panic(err)
+		}
+
+		registerCustomTranslations(trans, customMessages[locale])
+	}
+}
+
+// Struct validates payload against its validate tags and returns one localized message per
+// failing field, or nil when the payload is valid.
+func Struct(c *fiber.Ctx, payload any) []string {
+	return messages(c, validate.Struct(payload))
+}
+
+// Partial validates only the fields present in the JSON request body, so partial updates are
+// not rejected for omitting required fields they do not intend to change.
+func Partial(c *fiber.Ctx, payload any) []string {
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &present); err != nil {
+		return Struct(c, payload)
+	}
+
+	// Excluding the absent fields, rather than including the present ones, keeps the rules of
+	// nested structs and dive targets, whose namespaces an include list would filter out.
+	return messages(c, validate.StructExcept(payload, absentFields(reflect.TypeOf(payload), present)...))
+}
+
+func messages(c *fiber.Ctx, err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+
+	trans, _ := translator.GetTranslator(Locale(c))
+
+	result := make([]string, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		result = append(result, fieldError.Translate(trans))
+	}
+	return result
+}
+
+// Message is the localized summary to pair with the per-field messages.
+func Message(c *fiber.Ctx) string {
+	return failedMessages[Locale(c)]
+}
+
+// Locale picks the best supported language from the Accept-Language header.
+func Locale(c *fiber.Ctx) string {
+	if locale := c.AcceptsLanguages(locales...); locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// RegisterPatchFields makes the validate tags of patch.Field members apply to the value they hold.
+// The common scalar fields are registered already; packages register the others they use, such
+// as patches of slices, from init.
+func RegisterPatchFields(fields ...any) {
+	validate.RegisterCustomTypeFunc(patchValue, fields...)
+}
+
+func patchValue(field reflect.Value) any {
+	if f, ok := field.Interface().(interface{ Interface() any }); ok {
+		return f.Interface()
+	}
+	return nil
+}
+
+// absentFields lists the struct fields, by the names StructExcept expects, whose JSON keys are
+// missing from the body.
+func absentFields(t reflect.Type, present map[string]json.RawMessage) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := present[name]; !ok {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
+
+func isSlug(fl validator.FieldLevel) bool {
+	return slugPattern.MatchString(fl.Field().String())
+}
+
+// isUUID accepts uuid.UUID values that are not the zero UUID and strings that parse as a UUID.
+func isUUID(fl validator.FieldLevel) bool {
+	field := fl.Field()
+
+	if id, ok := field.Interface().(uuid.UUID); ok {
+		return id != uuid.Nil
+	}
+	if field.Kind() == reflect.String {
+		_, err := uuid.Parse(field.String())
+		return err == nil
+	}
+	return false
+}
+
+// hasFilePathPrefix accepts clean, relative storage paths without traversal. With a parameter,
+// e.g. filepath_prefix=avatars/ covers/, the path must also start with one of the prefixes.
+func hasFilePathPrefix(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" || strings.HasPrefix(value, "/") || strings.Contains(value, `\`) || strings.Contains(value, "://") {
+		return false
+	}
+	if path.Clean(value) != value || value == "." || strings.HasPrefix(value, "../") || value == ".." {
+		return false
+	}
+
+	prefixes := strings.Fields(fl.Param())
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustRegister(tag string, fn validator.Func) {
+	if err := validate.RegisterValidation(tag, fn); err != nil {
+		panic(err)
+	}
+}
+
+func registerCustomTranslations(trans ut.Translator, messages map[string]string) {
+	for _, tag := range []string{"slug", "uuid"} {
+		message := messages[tag]
+		registerTranslation(trans, tag, message, func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T(fe.Tag(), fe.Field())
+			return t
+		})
+	}
+
+	registerTranslation(trans, "filepath_prefix", messages["filepath_prefix"], func(ut ut.Translator, fe validator.FieldError) string {
+		if fe.Param() != "" {
+			t, _ := ut.T("filepath_prefix_with", fe.Field(), strings.Join(strings.Fields(fe.Param()), ", "))
+			return t
+		}
+		t, _ := ut.T(fe.Tag(), fe.Field())
+		return t
+	})
+	if err := trans.Add("filepath_prefix_with", messages["filepath_prefix_with"], true); err != nil {
+		panic(err)
+	}
+}
+
+func registerTranslation(trans ut.Translator, tag string, message string, fn validator.TranslationFunc) {
+	err := validate.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+		return ut.Add(tag, message, true)
+	}, fn)
+	if err != nil {
+		panic(err)
+	}
+}